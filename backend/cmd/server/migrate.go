@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/J0kerul/jokers-hub/internal/config"
+	"github.com/J0kerul/jokers-hub/internal/database"
+	"github.com/J0kerul/jokers-hub/internal/migrate"
+	"github.com/J0kerul/jokers-hub/migrations"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up            apply every pending migration
+  down          roll back the most recently applied migration
+  status        list every migration and whether it is applied
+  to N          apply or roll back until exactly the migrations up to N are applied
+  baseline N    record migrations up to N as applied without running them`
+
+// runMigrate handles the migrate subcommand of the server binary
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", migrateUsage)
+	}
+
+	// Commands taking a version need exactly one numeric argument
+	var version int
+	switch args[0] {
+	case "to", "baseline":
+		if len(args) != 2 {
+			return fmt.Errorf("%s needs a version\n%s", args[0], migrateUsage)
+		}
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		version = parsed
+	case "up", "down", "status":
+		if len(args) != 1 {
+			return fmt.Errorf("%s takes no arguments\n%s", args[0], migrateUsage)
+		}
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], migrateUsage)
+	}
+
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.NewPostgres(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator := migrate.NewMigrator(db, loaded)
+	migrator.BeforeUp(requireOwnersVersion, adoptUnownedRows(cfg.Auth))
+	migrator.BeforeUp(scopeTagsVersion, ensureTagOwner(cfg.Auth))
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx)
+	case "to":
+		return migrator.To(ctx, version)
+	case "baseline":
+		return migrator.Baseline(ctx, version)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+	return w.Flush()
+}
+
+// requireOwnersVersion is the migration that makes owner_id mandatory
+const requireOwnersVersion = 18
+
+// adoptUnownedRows hands rows left without owner by migration 014 to the
+// oldest user before migration 18 requires an owner. A database without users
+// gets the admin account from the configuration first, so `migrate up` works
+// on a fresh deployment that still holds data from before accounts existed.
+// The queries only rely on the schema as of version 17.
+func adoptUnownedRows(cfg config.AuthConfig) migrate.Hook {
+	return func(ctx context.Context, tx pgx.Tx) error {
+		var unowned bool
+		query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE owner_id IS NULL)
+			OR EXISTS (SELECT 1 FROM projects WHERE owner_id IS NULL)
+			OR EXISTS (SELECT 1 FROM phases WHERE owner_id IS NULL)
+			OR EXISTS (SELECT 1 FROM tech_stack_items WHERE owner_id IS NULL)`
+		if err := tx.QueryRow(ctx, query).Scan(&unowned); err != nil {
+			return fmt.Errorf("failed to look for unowned rows: %w", err)
+		}
+		if !unowned {
+			return nil
+		}
+
+		var ownerid uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT user_id FROM users ORDER BY created_at LIMIT 1`).Scan(&ownerid)
+		if errors.Is(err, pgx.ErrNoRows) {
+			ownerid, err = createAdmin(ctx, tx, cfg)
+		}
+		if err != nil {
+			return err
+		}
+
+		queries := []string{
+			`UPDATE tasks SET owner_id = $1 WHERE owner_id IS NULL`,
+			`UPDATE projects SET owner_id = $1 WHERE owner_id IS NULL`,
+			`UPDATE phases SET owner_id = $1 WHERE owner_id IS NULL`,
+			`UPDATE tech_stack_items SET owner_id = $1 WHERE owner_id IS NULL`,
+		}
+		for _, query := range queries {
+			if _, err := tx.Exec(ctx, query, ownerid); err != nil {
+				return fmt.Errorf("failed to adopt unowned rows: %w", err)
+			}
+		}
+
+		slog.Info("Adopted rows without owner", "user_id", ownerid)
+		return nil
+	}
+}
+
+// scopeTagsVersion is the migration that gives every tag and domain an owner
+const scopeTagsVersion = 19
+
+// ensureTagOwner creates the admin account when tags exist but no user does,
+// migration 19 hands the tags to the oldest user and would drop them otherwise
+func ensureTagOwner(cfg config.AuthConfig) migrate.Hook {
+	return func(ctx context.Context, tx pgx.Tx) error {
+		var orphaned bool
+		query := `SELECT EXISTS (SELECT 1 FROM tags) AND NOT EXISTS (SELECT 1 FROM users)`
+		if err := tx.QueryRow(ctx, query).Scan(&orphaned); err != nil {
+			return fmt.Errorf("failed to look for tags without user: %w", err)
+		}
+		if !orphaned {
+			return nil
+		}
+
+		_, err := createAdmin(ctx, tx, cfg)
+		return err
+	}
+}
+
+// createAdmin creates the account from ADMIN_EMAIL and ADMIN_PASSWORD, the
+// server would otherwise create it on its first start
+func createAdmin(ctx context.Context, tx pgx.Tx, cfg config.AuthConfig) (uuid.UUID, error) {
+	if cfg.AdminEmail == "" {
+		return uuid.Nil, fmt.Errorf("rows without owner left and no user to adopt them, set ADMIN_EMAIL and ADMIN_PASSWORD or run \"server user create\" first")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(cfg.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var userid uuid.UUID
+	query := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING user_id`
+	err = tx.QueryRow(ctx, query, strings.TrimSpace(cfg.AdminEmail), string(hash)).Scan(&userid)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create admin user: %w", err)
+	}
+
+	slog.Info("Created admin user", "email", cfg.AdminEmail)
+	return userid, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	"github.com/J0kerul/jokers-hub/internal/config"
+	"github.com/J0kerul/jokers-hub/internal/database"
+)
+
+const userUsage = `usage: server user create <email>
+
+The password is read from the first line of stdin, e.g.
+  echo "$PASSWORD" | server user create partner@example.com`
+
+// runUser handles the user subcommand of the server binary
+func runUser(cfg *config.Config, args []string) error {
+	if len(args) != 2 || args[0] != "create" {
+		return fmt.Errorf("%s", userUsage)
+	}
+
+	// Read the password without putting it on the command line
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	db, err := database.NewPostgres(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	service := auth.NewAuthService(auth.NewAuthRepo(db), cfg.Auth)
+	user, err := service.CreateUser(context.Background(), args[1], password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created user %s (%s)\n", user.Email, user.UserId)
+	return nil
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	UserId       uuid.UUID `json:"user_id" db:"user_id"`
+	Email        string    `json:"email" db:"email"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RefreshToken is the stored half of a refresh token, the plain token is only
+// ever handed to the client
+type RefreshToken struct {
+	TokenId   uuid.UUID  `json:"token_id" db:"token_id"`
+	UserId    uuid.UUID  `json:"user_id" db:"user_id"`
+	FamilyId  uuid.UUID  `json:"family_id" db:"family_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Scope limits what a personal API token may do
+type Scope string
+
+const (
+	ScopeRead          Scope = "read"
+	ScopeTasksWrite    Scope = "tasks:write"
+	ScopeProjectsWrite Scope = "projects:write"
+)
+
+// APIToken is a personal access token for scripts, like RefreshToken only
+// its hash is stored
+type APIToken struct {
+	TokenId    uuid.UUID  `json:"token_id" db:"token_id"`
+	UserId     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"token_prefix"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Scopes     []Scope    `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Session is the result of a login or refresh
+type Session struct {
+	User                  *User
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
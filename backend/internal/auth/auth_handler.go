@@ -0,0 +1,338 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// refreshCookieName is the HttpOnly cookie the refresh token is sent in,
+// scoped to the auth routes so it never travels with other requests
+const (
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/api/auth"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest is optional for browsers, which send the refresh cookie instead
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type UserResponse struct {
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SessionResponse struct {
+	AccessToken           string       `json:"access_token"`
+	TokenType             string       `json:"token_type"`
+	ExpiresIn             int          `json:"expires_in"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	User                  UserResponse `json:"user"`
+}
+
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []Scope    `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APITokenResponse struct {
+	TokenId    uuid.UUID  `json:"token_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []Scope    `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPITokenResponse is the only response that contains the plain token
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+type AuthHandler struct {
+	service       AuthServiceInterface
+	secureCookies bool
+}
+
+func NewAuthHandler(service AuthServiceInterface, secureCookies bool) *AuthHandler {
+	return &AuthHandler{
+		service:       service,
+		secureCookies: secureCookies,
+	}
+}
+
+// login handles POST /auth/login
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Request Body
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. Call Service Layer
+	session, err := h.service.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to log in")
+		return
+	}
+
+	// 3. Send Response
+	h.setRefreshCookie(w, session.RefreshToken, session.RefreshTokenExpiresAt)
+	utils.RespondWithJSON(w, http.StatusOK, sessionToResponse(session))
+}
+
+// refresh handles POST /auth/refresh, rotating the refresh token
+func (h *AuthHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	// 1. Read Refresh Token from Body or Cookie
+	refreshToken := readRefreshToken(r)
+
+	// 2. Call Service Layer
+	session, err := h.service.Refresh(r.Context(), refreshToken)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidRefreshToken) || errors.Is(err, errorutils.ErrRefreshTokenReused) {
+			h.clearRefreshCookie(w)
+			utils.RespondWithProblem(w, r, unwrapAuthError(err), "Failed to refresh session")
+			return
+		}
+		utils.RespondWithProblem(w, r, err, "Failed to refresh session")
+		return
+	}
+
+	// 3. Send Response
+	h.setRefreshCookie(w, session.RefreshToken, session.RefreshTokenExpiresAt)
+	utils.RespondWithJSON(w, http.StatusOK, sessionToResponse(session))
+}
+
+// logout handles POST /auth/logout, revoking the refresh token
+func (h *AuthHandler) logout(w http.ResponseWriter, r *http.Request) {
+	// 1. Read Refresh Token from Body or Cookie
+	refreshToken := readRefreshToken(r)
+
+	// 2. Call Service Layer
+	err := h.service.Logout(r.Context(), refreshToken)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to log out")
+		return
+	}
+
+	// 3. Send No Content Response
+	h.clearRefreshCookie(w)
+	utils.RespondWithNoContent(w)
+}
+
+// me handles GET /auth/me
+func (h *AuthHandler) me(w http.ResponseWriter, r *http.Request) {
+	// 1. Read User from Context
+	userid, ok := UserIdFromContext(r.Context())
+	if !ok {
+		utils.RespondWithUnauthorized(w, errorutils.ErrInvalidAccessToken.Error())
+		return
+	}
+
+	// 2. Call Service Layer
+	user, err := h.service.GetUserById(r.Context(), userid)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get user")
+		return
+	}
+
+	// 3. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, userToResponse(user))
+}
+
+// createAPIToken handles POST /auth/tokens
+func (h *AuthHandler) createAPIToken(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Request Body
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. Convert DTO to Entity
+	token := &APIToken{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	// 3. Call Service Layer
+	plain, err := h.service.CreateAPIToken(r.Context(), token)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to create api token")
+		return
+	}
+
+	// 4. Send Response
+	utils.RespondWithJSON(w, http.StatusCreated, CreatedAPITokenResponse{
+		APITokenResponse: apiTokenToResponse(token),
+		Token:            plain,
+	})
+}
+
+// getAPITokens handles GET /auth/tokens, revoked tokens are listed too
+func (h *AuthHandler) getAPITokens(w http.ResponseWriter, r *http.Request) {
+	// 1. Call Service Layer
+	tokens, err := h.service.GetAPITokens(r.Context())
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve api tokens")
+		return
+	}
+
+	// 2. Convert Entities to Response DTOs
+	response := make([]APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		response[i] = apiTokenToResponse(token)
+	}
+
+	// 3. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// revokeAPIToken handles DELETE /auth/tokens/:id
+func (h *AuthHandler) revokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid token ID")
+		return
+	}
+
+	// 2. Call Service Layer
+	err = h.service.RevokeAPIToken(r.Context(), tokenID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to revoke api token")
+		return
+	}
+
+	// 3. Send No Content Response
+	utils.RespondWithNoContent(w)
+}
+
+// readRefreshToken prefers the JSON body and falls back to the cookie
+func readRefreshToken(r *http.Request) string {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err == nil && req.RefreshToken != "" {
+		return req.RefreshToken
+	}
+
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func (h *AuthHandler) setRefreshCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, h.refreshCookie(token, expiresAt, int(time.Until(expiresAt).Seconds())))
+}
+
+func (h *AuthHandler) clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, h.refreshCookie("", time.Unix(0, 0), -1))
+}
+
+// refreshCookie builds the cookie, cross-site requests from the deployed
+// frontend need SameSite=None which browsers only accept on Secure cookies
+func (h *AuthHandler) refreshCookie(value string, expiresAt time.Time, maxAge int) *http.Cookie {
+	sameSite := http.SameSiteLaxMode
+	if h.secureCookies {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    value,
+		Path:     refreshCookiePath,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: sameSite,
+	}
+}
+
+// unwrapAuthError returns the auth sentinel inside err so no internals leak
+func unwrapAuthError(err error) error {
+	if errors.Is(err, errorutils.ErrRefreshTokenReused) {
+		return errorutils.ErrRefreshTokenReused
+	}
+	return errorutils.ErrInvalidRefreshToken
+}
+
+// sessionToResponse converts a Session to the response DTO
+func sessionToResponse(session *Session) SessionResponse {
+	return SessionResponse{
+		AccessToken:           session.AccessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int(time.Until(session.AccessTokenExpiresAt).Seconds()),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: session.RefreshTokenExpiresAt,
+		User:                  userToResponse(session.User),
+	}
+}
+
+// userToResponse converts User entity to response DTO
+func userToResponse(user *User) UserResponse {
+	return UserResponse{
+		UserId:    user.UserId,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// apiTokenToResponse converts APIToken entity to response DTO
+func apiTokenToResponse(token *APIToken) APITokenResponse {
+	return APITokenResponse{
+		TokenId:    token.TokenId,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// RegisterRoutes registers the auth routes. Login, refresh and logout are
+// public, everything else needs an access token. API tokens can only be
+// managed from a session.
+func RegisterRoutes(r chi.Router, handler *AuthHandler) {
+	r.Route("/auth", func(r chi.Router) {
+		// Session
+		r.Post("/login", handler.login)     // POST /auth/login
+		r.Post("/refresh", handler.refresh) // POST /auth/refresh
+		r.Post("/logout", handler.logout)   // POST /auth/logout
+
+		// Read
+		r.With(RequireAuth(handler.service)).Get("/me", handler.me) // GET /auth/me
+
+		// API Tokens
+		r.Route("/tokens", func(r chi.Router) {
+			r.Use(RequireAuth(handler.service), RequireSession)
+
+			r.Post("/", handler.createAPIToken)       // POST /auth/tokens
+			r.Get("/", handler.getAPITokens)          // GET /auth/tokens
+			r.Delete("/{id}", handler.revokeAPIToken) // DELETE /auth/tokens/:id
+		})
+	})
+}
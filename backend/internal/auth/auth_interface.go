@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type AuthRepositoryInterface interface {
+	CountUsers(ctx context.Context) (int, error)
+	CreateUser(ctx context.Context, user *User) error
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserById(ctx context.Context, userid uuid.UUID) (*User, error)
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	RotateRefreshToken(ctx context.Context, tokenHash string, next *RefreshToken) error
+	RevokeRefreshTokenFamily(ctx context.Context, tokenHash string) error
+	CreateAPIToken(ctx context.Context, token *APIToken) error
+	GetAPITokens(ctx context.Context) ([]*APIToken, error)
+	RevokeAPIToken(ctx context.Context, tokenid uuid.UUID) error
+	UseAPIToken(ctx context.Context, tokenHash string) (*APIToken, error)
+}
+
+type AuthServiceInterface interface {
+	EnsureUser(ctx context.Context, email string, password string) error
+	CreateUser(ctx context.Context, email string, password string) (*User, error)
+	Login(ctx context.Context, email string, password string) (*Session, error)
+	Refresh(ctx context.Context, refreshToken string) (*Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+	ParseAccessToken(accessToken string) (uuid.UUID, error)
+	GetUserById(ctx context.Context, userid uuid.UUID) (*User, error)
+	CreateAPIToken(ctx context.Context, token *APIToken) (string, error)
+	GetAPITokens(ctx context.Context) ([]*APIToken, error)
+	RevokeAPIToken(ctx context.Context, tokenid uuid.UUID) error
+	AuthenticateAPIToken(ctx context.Context, token string) (*APIToken, error)
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type contextKey int
+
+const (
+	// userIdKey holds the id of the authenticated user in the request context
+	userIdKey contextKey = iota
+	// scopesKey holds the scopes of the API token a request was made with,
+	// it is absent for requests made with a session access token
+	scopesKey
+)
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer" access
+// token or personal API token and stores the id of the authenticated user in
+// the request context
+func RequireAuth(service AuthServiceInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 1. Read Bearer Token
+			scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				utils.RespondWithUnauthorized(w, "Missing bearer token")
+				return
+			}
+
+			// 2. Verify Token
+			token = strings.TrimSpace(token)
+			ctx := r.Context()
+			if strings.HasPrefix(token, APITokenPrefix) {
+				apiToken, err := service.AuthenticateAPIToken(ctx, token)
+				if err != nil {
+					respondWithInvalidToken(w, r, err)
+					return
+				}
+				ctx = context.WithValue(WithUserId(ctx, apiToken.UserId), scopesKey, apiToken.Scopes)
+			} else {
+				userid, err := service.ParseAccessToken(token)
+				if err != nil {
+					respondWithInvalidToken(w, r, err)
+					return
+				}
+				ctx = WithUserId(ctx, userid)
+			}
+
+			// 3. Continue with the User in the Context
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authorize limits API tokens to their scopes. Reads need the read scope or
+// writeScope, everything else needs writeScope. An empty writeScope leaves
+// writes to sessions. Session requests are never limited.
+func Authorize(writeScope Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, ok := scopesFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed := writeScope != "" && slices.Contains(scopes, writeScope)
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				allowed = allowed || slices.Contains(scopes, ScopeRead)
+			}
+			if !allowed {
+				utils.RespondWithProblem(w, r, errorutils.ErrInsufficientScope, "Failed to authorize request")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects requests made with an API token, so a leaked token
+// can't be used to mint or revoke tokens
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := scopesFromContext(r.Context()); ok {
+			utils.RespondWithProblem(w, r, errorutils.ErrSessionRequired, "Failed to authorize request")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// respondWithInvalidToken answers 401 for rejected tokens and 500 when the
+// token couldn't be checked at all
+func respondWithInvalidToken(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, errorutils.ErrInvalidAccessToken) {
+		utils.RespondWithServerError(w, r, err, "Failed to verify token")
+		return
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+	utils.RespondWithProblem(w, r, errorutils.ErrInvalidAccessToken, "Failed to verify token")
+}
+
+// scopesFromContext returns the scopes of the API token the request was made with
+func scopesFromContext(ctx context.Context) ([]Scope, bool) {
+	scopes, ok := ctx.Value(scopesKey).([]Scope)
+	return scopes, ok
+}
+
+// WithUserId returns a copy of ctx carrying the id of the authenticated user
+func WithUserId(ctx context.Context, userid uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIdKey, userid)
+}
+
+// UserIdFromContext returns the id stored by RequireAuth
+func UserIdFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userid, ok := ctx.Value(userIdKey).(uuid.UUID)
+	return userid, ok
+}
+
+// OwnerFromContext returns the authenticated user that repositories scope
+// their queries to. It fails closed for requests that never passed RequireAuth.
+func OwnerFromContext(ctx context.Context) (uuid.UUID, error) {
+	userid, ok := UserIdFromContext(ctx)
+	if !ok || userid == uuid.Nil {
+		return uuid.Nil, errorutils.ErrMissingOwner
+	}
+	return userid, nil
+}
@@ -0,0 +1,363 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUniqueViolation is the Postgres error code for a violated unique constraint
+const pgUniqueViolation = "23505"
+
+const userSelect = `SELECT user_id, email, password_hash, created_at, updated_at FROM users`
+
+const apiTokenColumns = `token_id, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+const apiTokenSelect = `SELECT ` + apiTokenColumns + ` FROM api_tokens`
+
+type AuthRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuthRepo(db *pgxpool.Pool) *AuthRepo {
+	return &AuthRepo{db: db}
+}
+
+func (r *AuthRepo) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// CreateUser stores the user together with a copy of the default domains. The
+// first user adopts the rows created before accounts existed, which migration
+// 014 had to leave without owner. Migration 018 only applies once they are adopted.
+func (r *AuthRepo) CreateUser(ctx context.Context, user *User) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Lock the table so two users created at once can't both count as the first
+	_, err = tx.Exec(ctx, `LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return fmt.Errorf("failed to lock users: %w", err)
+	}
+
+	var count int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING user_id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		user.Email,
+		user.PasswordHash,
+	).Scan(&user.UserId, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errorutils.ErrEmailTaken
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// Every user files tasks under their own domains
+	query = `INSERT INTO domains (slug, display_name, color, icon, position, owner_id)
+		SELECT slug, display_name, color, icon, position, $1 FROM default_domains`
+	_, err = tx.Exec(ctx, query, user.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to create default domains: %w", err)
+	}
+
+	// Later users start empty, unowned rows are never theirs
+	if count > 0 {
+		return tx.Commit(ctx)
+	}
+
+	for _, table := range []string{"tasks", "projects", "phases", "tech_stack_items"} {
+		_, err = tx.Exec(ctx, `UPDATE `+table+` SET owner_id = $1 WHERE owner_id IS NULL`, user.UserId)
+		if err != nil {
+			return fmt.Errorf("failed to adopt unowned %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetUserByEmail returns ErrInvalidCredentials when no user has the email
+func (r *AuthRepo) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	query := userSelect + ` WHERE LOWER(email) = LOWER($1)`
+	user, err := scanUser(r.db.QueryRow(ctx, query, email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return user, nil
+}
+
+func (r *AuthRepo) GetUserById(ctx context.Context, userid uuid.UUID) (*User, error) {
+	query := userSelect + ` WHERE user_id = $1`
+	user, err := scanUser(r.db.QueryRow(ctx, query, userid))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+	return user, nil
+}
+
+// CreateRefreshToken stores the token and drops expired tokens of the user on the way
+func (r *AuthRepo) CreateRefreshToken(ctx context.Context, token *RefreshToken) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at < NOW()`, token.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	return insertRefreshToken(ctx, r.db, token)
+}
+
+// RotateRefreshToken revokes the token with the hash and stores next in its
+// family. Presenting an already revoked token means it leaked, so the whole
+// family is revoked and ErrRefreshTokenReused is returned.
+func (r *AuthRepo) RotateRefreshToken(ctx context.Context, tokenHash string, next *RefreshToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Lock the token so two concurrent refreshes can't both rotate it
+	var current RefreshToken
+	err = tx.QueryRow(ctx, `SELECT token_id, user_id, family_id, expires_at, revoked_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`, tokenHash).Scan(
+		&current.TokenId,
+		&current.UserId,
+		&current.FamilyId,
+		&current.ExpiresAt,
+		&current.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrInvalidRefreshToken
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if current.RevokedAt != nil {
+		_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, current.FamilyId)
+		if err != nil {
+			return fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit refresh token revocation: %w", err)
+		}
+		return errorutils.ErrRefreshTokenReused
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return errorutils.ErrInvalidRefreshToken
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE token_id = $1`, current.TokenId)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	next.UserId = current.UserId
+	next.FamilyId = current.FamilyId
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeRefreshTokenFamily ends the login session the token belongs to
+func (r *AuthRepo) RevokeRefreshTokenFamily(ctx context.Context, tokenHash string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)
+		AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthRepo) CreateAPIToken(ctx context.Context, token *APIToken) error {
+	ownerId, err := OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO api_tokens (user_id, name, token_prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING token_id, user_id, created_at`
+	err = r.db.QueryRow(ctx, query,
+		ownerId,
+		token.Name,
+		token.Prefix,
+		token.TokenHash,
+		scopeStrings(token.Scopes),
+		token.ExpiresAt,
+	).Scan(&token.TokenId, &token.UserId, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthRepo) GetAPITokens(ctx context.Context) ([]*APIToken, error) {
+	ownerId, err := OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := apiTokenSelect + ` WHERE user_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.Query(ctx, query, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*APIToken, 0)
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken returns ErrUnknownAPIToken unless the user has an active token with the id
+func (r *AuthRepo) RevokeAPIToken(ctx context.Context, tokenid uuid.UUID) error {
+	ownerId, err := OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `UPDATE api_tokens SET revoked_at = NOW() WHERE token_id = $1 AND user_id = $2 AND revoked_at IS NULL`, tokenid, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrUnknownAPIToken
+	}
+	return nil
+}
+
+// UseAPIToken returns the active token with the hash and records the use.
+// Unknown, revoked and expired tokens all return ErrInvalidAccessToken.
+func (r *AuthRepo) UseAPIToken(ctx context.Context, tokenHash string) (*APIToken, error) {
+	query := `UPDATE api_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING ` + apiTokenColumns
+	token, err := scanAPIToken(r.db.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrInvalidAccessToken
+		}
+		return nil, fmt.Errorf("failed to use api token: %w", err)
+	}
+	return token, nil
+}
+
+// querier is implemented by both the pool and a transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertRefreshToken(ctx context.Context, q querier, token *RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING token_id, created_at`
+	err := q.QueryRow(ctx, query,
+		token.UserId,
+		token.FamilyId,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&token.TokenId, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err was caused by a violated unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// scanUser scans a row selected with userSelect into a User
+func scanUser(row pgx.Row) (*User, error) {
+	var user User
+	err := row.Scan(
+		&user.UserId,
+		&user.Email,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// scanAPIToken scans a row selected with apiTokenColumns into an APIToken
+func scanAPIToken(row pgx.Row) (*APIToken, error) {
+	var token APIToken
+	var scopes []string
+	err := row.Scan(
+		&token.TokenId,
+		&token.UserId,
+		&token.Name,
+		&token.Prefix,
+		&token.TokenHash,
+		&scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = make([]Scope, len(scopes))
+	for i, scope := range scopes {
+		token.Scopes[i] = Scope(scope)
+	}
+	return &token, nil
+}
+
+// scopeStrings converts scopes for the TEXT[] column
+func scopeStrings(scopes []Scope) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return values
+}
@@ -0,0 +1,353 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/J0kerul/jokers-hub/internal/config"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenIssuer is the iss claim of every access token
+const tokenIssuer = "jokers-hub"
+
+// minPasswordLength matches the ADMIN_PASSWORD check of the config package
+const minPasswordLength = 12
+
+// APITokenPrefix starts every personal API token, so RequireAuth can tell them
+// apart from access tokens and leaked tokens are easy to grep for
+const APITokenPrefix = "jhp_"
+
+// apiTokenDisplayLength is how much of a token is stored in clear to recognise it in lists
+const apiTokenDisplayLength = 12
+
+// dummyHash is compared against when the email is unknown, so a login takes
+// as long for unknown emails as for wrong passwords
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+type AuthService struct {
+	repo            AuthRepositoryInterface
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewAuthService(repo AuthRepositoryInterface, cfg config.AuthConfig) *AuthService {
+	return &AuthService{
+		repo:            repo,
+		secret:          []byte(cfg.JWTSecret),
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+	}
+}
+
+// EnsureUser creates the first user while the users table is still empty
+func (s *AuthService) EnsureUser(ctx context.Context, email string, password string) error {
+	count, err := s.repo.CountUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = s.CreateUser(ctx, email, password)
+	return err
+}
+
+func (s *AuthService) CreateUser(ctx context.Context, email string, password string) (*User, error) {
+	// Check for required fields
+	email = strings.TrimSpace(email)
+	if email == "" || password == "" {
+		return nil, errorutils.ErrMissingCredentials
+	}
+	if len(password) < minPasswordLength {
+		return nil, errorutils.ErrPasswordTooShort
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &User{
+		Email:        email,
+		PasswordHash: string(hash),
+	}
+	err = s.repo.CreateUser(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *AuthService) Login(ctx context.Context, email string, password string) (*Session, error) {
+	// Check for required fields
+	email = strings.TrimSpace(email)
+	if email == "" || password == "" {
+		return nil, errorutils.ErrMissingCredentials
+	}
+
+	// Unknown emails still pay for a bcrypt comparison
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidCredentials) {
+			bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+			return nil, errorutils.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		return nil, errorutils.ErrInvalidCredentials
+	}
+
+	// Every login starts a new refresh token family
+	refreshToken, stored, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	stored.UserId = user.UserId
+	stored.FamilyId = uuid.New()
+
+	err = s.repo.CreateRefreshToken(ctx, stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return s.newSession(user, refreshToken, stored)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh token
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	if refreshToken == "" {
+		return nil, errorutils.ErrInvalidRefreshToken
+	}
+
+	nextToken, stored, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.RotateRefreshToken(ctx, hashToken(refreshToken), stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	user, err := s.repo.GetUserById(ctx, stored.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.newSession(user, nextToken, stored)
+}
+
+// Logout revokes the refresh token and every token rotated from the same login
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	err := s.repo.RevokeRefreshTokenFamily(ctx, hashToken(refreshToken))
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// ParseAccessToken verifies the signature, issuer and expiry of the token and
+// returns the id of the user it was issued to
+func (s *AuthService) ParseAccessToken(accessToken string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (any, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, errorutils.ErrInvalidAccessToken
+	}
+
+	userid, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, errorutils.ErrInvalidAccessToken
+	}
+
+	return userid, nil
+}
+
+func (s *AuthService) GetUserById(ctx context.Context, userid uuid.UUID) (*User, error) {
+	// Check if id isn't empty
+	if userid == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	user, err := s.repo.GetUserById(ctx, userid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+
+	return user, nil
+}
+
+// CreateAPIToken stores the token and returns its plain value, which is only
+// ever shown this once
+func (s *AuthService) CreateAPIToken(ctx context.Context, token *APIToken) (string, error) {
+	// Check for required fields
+	token.Name = strings.TrimSpace(token.Name)
+	if token.Name == "" {
+		return "", errorutils.ErrNameRequired
+	}
+
+	scopes, err := normalizeScopes(token.Scopes)
+	if err != nil {
+		return "", err
+	}
+	token.Scopes = scopes
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return "", errorutils.ErrExpiryInPast
+	}
+
+	// Generate the token
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	plain := APITokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	token.Prefix = plain[:apiTokenDisplayLength]
+	token.TokenHash = hashToken(plain)
+
+	err = s.repo.CreateAPIToken(ctx, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	return plain, nil
+}
+
+func (s *AuthService) GetAPITokens(ctx context.Context) ([]*APIToken, error) {
+	tokens, err := s.repo.GetAPITokens(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (s *AuthService) RevokeAPIToken(ctx context.Context, tokenid uuid.UUID) error {
+	// Check if id isn't empty
+	if tokenid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.RevokeAPIToken(ctx, tokenid)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIToken returns the active token matching the plain value and
+// records its use
+func (s *AuthService) AuthenticateAPIToken(ctx context.Context, token string) (*APIToken, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, errorutils.ErrInvalidAccessToken
+	}
+
+	apiToken, err := s.repo.UseAPIToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidAccessToken) {
+			return nil, errorutils.ErrInvalidAccessToken
+		}
+		return nil, fmt.Errorf("failed to authenticate api token: %w", err)
+	}
+
+	return apiToken, nil
+}
+
+// normalizeScopes rejects empty and unknown scopes and drops duplicates
+func normalizeScopes(scopes []Scope) ([]Scope, error) {
+	if len(scopes) == 0 {
+		return nil, errorutils.ErrMissingScopes
+	}
+
+	seen := make(map[Scope]bool, len(scopes))
+	normalized := make([]Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		switch scope {
+		case ScopeRead, ScopeTasksWrite, ScopeProjectsWrite:
+		default:
+			return nil, errorutils.ErrInvalidScope
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+
+	return normalized, nil
+}
+
+// newSession signs an access token for the user and pairs it with the refresh token
+func (s *AuthService) newSession(user *User, refreshToken string, stored *RefreshToken) (*Session, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.accessTokenTTL)
+
+	claims := jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   user.UserId.String(),
+		ID:        uuid.NewString(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &Session{
+		User:                  user,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+// newRefreshToken returns a random token for the client and its stored counterpart
+func (s *AuthService) newRefreshToken() (string, *RefreshToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	return token, &RefreshToken{
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}, nil
+}
+
+// hashToken returns the hex sha256 of a token, tokens are random enough that
+// a slow hash isn't needed
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package cors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/J0kerul/jokers-hub/internal/config"
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	chicors "github.com/go-chi/cors"
+)
+
+// exposedHeaders are response headers the frontend reads
+var exposedHeaders = []string{"ETag", "X-Next-Cursor"}
+
+// originMatcher holds the allowed origins, split into exact origins and
+// wildcard subdomain patterns
+type originMatcher struct {
+	all      bool
+	exact    map[string]bool
+	patterns []originPattern
+}
+
+// originPattern is https://*.example.com split into "https://" and ".example.com"
+type originPattern struct {
+	scheme string
+	suffix string
+}
+
+// New returns the CORS middleware for the configured origins. Preflight
+// requests from other origins are answered with 403, actual requests from
+// them get no CORS headers, so the browser blocks the response.
+func New(cfg config.CORSConfig) func(http.Handler) http.Handler {
+	matcher := newOriginMatcher(cfg.AllowedOrigins)
+
+	c := chicors.New(chicors.Options{
+		AllowOriginFunc: func(r *http.Request, origin string) bool {
+			return matcher.allows(origin)
+		},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "X-Requested-With"},
+		ExposedHeaders:   exposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+
+	return func(next http.Handler) http.Handler {
+		handler := c.Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPreflight(r) && !matcher.allows(r.Header.Get("Origin")) {
+				w.Header().Add("Vary", "Origin")
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				utils.RespondWithForbidden(w, "Origin not allowed")
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+func newOriginMatcher(origins []string) *originMatcher {
+	matcher := &originMatcher{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		if origin == "*" {
+			matcher.all = true
+			continue
+		}
+
+		scheme, host, _ := strings.Cut(origin, "://")
+		if rest, ok := strings.CutPrefix(host, "*."); ok {
+			matcher.patterns = append(matcher.patterns, originPattern{
+				scheme: scheme + "://",
+				suffix: "." + rest,
+			})
+			continue
+		}
+		matcher.exact[origin] = true
+	}
+	return matcher
+}
+
+// allows reports whether the origin may call the API. A pattern matches
+// subdomains at any depth but never the bare domain itself.
+func (m *originMatcher) allows(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if m.all {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+
+	for _, pattern := range m.patterns {
+		host, ok := strings.CutPrefix(origin, pattern.scheme)
+		if !ok {
+			continue
+		}
+		subdomain, ok := strings.CutSuffix(host, pattern.suffix)
+		if ok && isSubdomain(subdomain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubdomain reports whether s is made of dot separated hostname labels
+func isSubdomain(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isPreflight reports whether r is a CORS preflight request
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
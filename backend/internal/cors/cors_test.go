@@ -0,0 +1,103 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/config"
+)
+
+func TestOriginMatcherAllows(t *testing.T) {
+	matcher := newOriginMatcher([]string{"https://*.example.com", "http://localhost:5173/", "https://*.example.org:8443"})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		// Exact origins
+		{"http://localhost:5173", true},
+		{"http://localhost:3000", false},
+		{"https://localhost:5173", false},
+
+		// Wildcard subdomains at any depth
+		{"https://app.example.com", true},
+		{"https://staging.app.example.com", true},
+		{"https://App.Example.COM", true},
+		{"https://app-1.example.com", true},
+
+		// The bare domain and look-alikes
+		{"https://example.com", false},
+		{"https://.example.com", false},
+		{"https://evil-example.com", false},
+		{"https://app.evil-example.com", false},
+		{"https://example.com.evil.io", false},
+		{"https://app.example.com.evil.io", false},
+		{"https://evil.io#.example.com", false},
+		{"https://evil.io/.example.com", false},
+		{"https://user@evil.io?.example.com", false},
+		{"https://app_1.example.com", false},
+
+		// Scheme and port have to match the pattern
+		{"http://app.example.com", false},
+		{"https://app.example.com:8443", false},
+		{"https://app.example.org:8443", true},
+		{"https://app.example.org", false},
+		{"https://app.example.org:9443", false},
+
+		// Browsers send "null" for opaque origins
+		{"null", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			if got := matcher.allows(tt.origin); got != tt.want {
+				t.Fatalf("allows(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOriginMatcherAllowsAll(t *testing.T) {
+	matcher := newOriginMatcher([]string{"*"})
+
+	if !matcher.allows("https://anything.example") {
+		t.Fatal("wildcard origin didn't allow an origin")
+	}
+	if matcher.allows("") {
+		t.Fatal("wildcard origin allowed a request without origin")
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	handler := New(config.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"https://example.com.evil.io", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/tasks", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package dbtest connects repository tests to the Postgres database named by
+// TEST_DATABASE_URL. Tests using it are skipped when the variable is unset,
+// except in CI, where a missing database fails them.
+package dbtest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	"github.com/J0kerul/jokers-hub/internal/migrate"
+	"github.com/J0kerul/jokers-hub/migrations"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Open migrates the test database to the latest version and returns a pool
+// that is closed when the test ends
+func Open(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("TEST_DATABASE_URL is not set, CI has to run the repository tests")
+		}
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	err = migrate.NewMigrator(db, loaded).Up(ctx)
+	if err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// UserContext creates a user with a unique email and the default domains and
+// returns a context authenticated as that user. The user and everything it
+// owns are deleted when the test ends.
+func UserContext(t *testing.T, db *pgxpool.Pool) context.Context {
+	t.Helper()
+
+	ctx := context.Background()
+	user := &auth.User{Email: uuid.NewString() + "@example.com", PasswordHash: "unused"}
+	err := auth.NewAuthRepo(db).CreateUser(ctx, user)
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(context.Background(), `DELETE FROM users WHERE user_id = $1`, user.UserId)
+	})
+
+	return auth.WithUserId(ctx, user.UserId)
+}
@@ -0,0 +1,8 @@
+package database
+
+// OverdueCondition returns the SQL condition shared by every query counting
+// overdue tasks, alias is the name the tasks table goes by. Deadlines are
+// dates, so a task due today isn't overdue before the day is over.
+func OverdueCondition(alias string) string {
+	return "NOT " + alias + ".completed AND " + alias + ".deadline < CURRENT_DATE"
+}
@@ -3,21 +3,26 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/J0kerul/jokers-hub/internal/config"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPostgres() (*pgxpool.Pool, error) {
-	ctx := context.Background()
-	cfg, err := config.NewConfig()
+// NewPostgres creates the connection pool and retries until the database
+// answers, so the server can start while Postgres is still booting
+func NewPostgres(cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
 	}
 
-	pgxstring := "postgres://" + cfg.DatabaseUser + ":" + cfg.DatabasePassword + "@" + cfg.DatabaseHost + ":" + cfg.DatabasePort + "/" + cfg.DatabaseName
+	// Connection pool settings
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
 
 	maxRetries := 10
 	retryDelay := 2 * time.Second
@@ -25,21 +30,23 @@ func NewPostgres() (*pgxpool.Pool, error) {
 	var pool *pgxpool.Pool
 
 	for i := 0; i < maxRetries; i++ {
-		pool, err = pgxpool.New(ctx, pgxstring)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+		pool, err = pgxpool.NewWithConfig(ctx, poolConfig)
 		if err != nil {
-			log.Printf("Failed to create connection pool (attempt %d/%d): %v", i+1, maxRetries, err)
+			cancel()
+			slog.Warn("Failed to create connection pool", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 			time.Sleep(retryDelay)
 			continue
 		}
 
 		// Try to ping
 		err = pool.Ping(ctx)
+		cancel()
 		if err == nil {
-			log.Println("Successfully connected to database!")
 			return pool, nil
 		}
 
-		log.Printf("Failed to ping database (attempt %d/%d): %v", i+1, maxRetries, err)
+		slog.Warn("Failed to ping database", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 		pool.Close()
 		time.Sleep(retryDelay)
 	}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain is a life area tasks are filed under. Tasks reference it by slug.
+type Domain struct {
+	DomainId    uuid.UUID `json:"domain_id" db:"domain_id"`
+	Slug        string    `json:"slug" db:"slug"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	Color       string    `json:"color" db:"color"`
+	Icon        string    `json:"icon" db:"icon"`
+	Position    int       `json:"position" db:"position"`
+	Archived    bool      `json:"archived" db:"archived"`
+	TaskCount   int       `json:"task_count" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,316 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CreateDomainRequest struct {
+	Slug        string `json:"slug"`
+	DisplayName string `json:"display_name"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+}
+
+type UpdateDomainRequest struct {
+	Slug        *string `json:"slug,omitempty"`
+	DisplayName *string `json:"display_name,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Icon        *string `json:"icon,omitempty"`
+	Archived    *bool   `json:"archived,omitempty"`
+}
+
+type ReorderDomainsRequest struct {
+	DomainIds []uuid.UUID `json:"domain_ids"`
+}
+
+type MergeDomainRequest struct {
+	IntoDomainId uuid.UUID `json:"into_domain_id"`
+}
+
+type DomainResponse struct {
+	DomainId    uuid.UUID `json:"domain_id"`
+	Slug        string    `json:"slug"`
+	DisplayName string    `json:"display_name"`
+	Color       string    `json:"color"`
+	Icon        string    `json:"icon"`
+	Position    int       `json:"position"`
+	Archived    bool      `json:"archived"`
+	TaskCount   int       `json:"task_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type DomainHandler struct {
+	service DomainServiceInterface
+}
+
+func NewDomainHandler(service DomainServiceInterface) *DomainHandler {
+	return &DomainHandler{
+		service: service,
+	}
+}
+
+// createDomain handles POST /domains
+func (h *DomainHandler) createDomain(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Request Body
+	var req CreateDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. DTO → Entity
+	domain := &Domain{
+		Slug:        req.Slug,
+		DisplayName: req.DisplayName,
+		Color:       req.Color,
+		Icon:        req.Icon,
+	}
+
+	// 3. Call Service Layer
+	err := h.service.CreateDomain(r.Context(), domain)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to create domain")
+		return
+	}
+
+	// 4. Send Response
+	utils.RespondWithJSON(w, http.StatusCreated, domainToResponse(domain))
+}
+
+// getAllDomains handles GET /domains, ?include_archived=true also lists archived domains
+func (h *DomainHandler) getAllDomains(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Query
+	includeArchived := false
+	if v := r.URL.Query().Get("include_archived"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.RespondWithBadRequest(w, "invalid include_archived value")
+			return
+		}
+		includeArchived = parsed
+	}
+
+	// 2. Call Service Layer
+	domains, err := h.service.GetAllDomains(r.Context(), includeArchived)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve domains")
+		return
+	}
+
+	// 3. Entity → Response DTOs
+	response := make([]DomainResponse, len(domains))
+	for i, domain := range domains {
+		response[i] = domainToResponse(domain)
+	}
+
+	// 4. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// getDomainById handles GET /domains/:id
+func (h *DomainHandler) getDomainById(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	domainID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid domain ID")
+		return
+	}
+
+	// 2. Call Service Layer
+	domain, err := h.service.GetDomainById(r.Context(), domainID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get domain")
+		return
+	}
+
+	// 3. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, domainToResponse(domain))
+}
+
+// updateDomain handles PUT /domains/:id
+func (h *DomainHandler) updateDomain(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	domainID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid domain ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req UpdateDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Existing Domain
+	domain, err := h.service.GetDomainById(r.Context(), domainID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get domain")
+		return
+	}
+
+	// 4. Update Fields
+	if req.Slug != nil {
+		domain.Slug = *req.Slug
+	}
+	if req.DisplayName != nil {
+		domain.DisplayName = *req.DisplayName
+	}
+	if req.Color != nil {
+		domain.Color = *req.Color
+	}
+	if req.Icon != nil {
+		domain.Icon = *req.Icon
+	}
+	if req.Archived != nil {
+		domain.Archived = *req.Archived
+	}
+
+	// 5. Call Service Layer
+	err = h.service.UpdateDomain(r.Context(), domain)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to update domain")
+		return
+	}
+
+	// 6. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, domainToResponse(domain))
+}
+
+// deleteDomain handles DELETE /domains/:id
+func (h *DomainHandler) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	domainID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid domain ID")
+		return
+	}
+
+	// 2. Call Service Layer to Delete Domain
+	err = h.service.DeleteDomain(r.Context(), domainID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to delete domain")
+		return
+	}
+
+	// 3. Send No Content Response
+	utils.RespondWithNoContent(w)
+}
+
+// reorderDomains handles PUT /domains/reorder
+func (h *DomainHandler) reorderDomains(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Request Body
+	var req ReorderDomainsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. Call Service Layer to Rewrite the Positions
+	err := h.service.ReorderDomains(r.Context(), req.DomainIds)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to reorder domains")
+		return
+	}
+
+	// 3. Respond with every Domain in its new Order
+	domains, err := h.service.GetAllDomains(r.Context(), true)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve domains")
+		return
+	}
+	response := make([]DomainResponse, len(domains))
+	for i, domain := range domains {
+		response[i] = domainToResponse(domain)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// mergeDomain handles POST /domains/:id/merge, refiling its tasks under into_domain_id
+func (h *DomainHandler) mergeDomain(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	domainID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid domain ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req MergeDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Both Domains
+	source, err := h.service.GetDomainById(r.Context(), domainID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get domain")
+		return
+	}
+	target, err := h.service.GetDomainById(r.Context(), req.IntoDomainId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get target domain")
+		return
+	}
+
+	// 4. Call Service Layer
+	err = h.service.MergeDomains(r.Context(), source, target)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to merge domains")
+		return
+	}
+
+	// 5. Respond with the Surviving Domain
+	target, err = h.service.GetDomainById(r.Context(), target.DomainId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve merged domain")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, domainToResponse(target))
+}
+
+// domainToResponse converts Domain entity to response DTO
+func domainToResponse(domain *Domain) DomainResponse {
+	return DomainResponse{
+		DomainId:    domain.DomainId,
+		Slug:        domain.Slug,
+		DisplayName: domain.DisplayName,
+		Color:       domain.Color,
+		Icon:        domain.Icon,
+		Position:    domain.Position,
+		Archived:    domain.Archived,
+		TaskCount:   domain.TaskCount,
+		CreatedAt:   domain.CreatedAt,
+		UpdatedAt:   domain.UpdatedAt,
+	}
+}
+
+// RegisterRoutes registers all domain-related routes
+func RegisterRoutes(r chi.Router, handler *DomainHandler) {
+	r.Route("/domains", func(r chi.Router) {
+		// Create
+		r.Post("/", handler.createDomain) // POST /domains
+
+		// Read
+		r.Get("/", handler.getAllDomains)     // GET /domains
+		r.Get("/{id}", handler.getDomainById) // GET /domains/:id
+
+		// Update
+		r.Put("/reorder", handler.reorderDomains)  // PUT /domains/reorder
+		r.Put("/{id}", handler.updateDomain)       // PUT /domains/:id
+		r.Post("/{id}/merge", handler.mergeDomain) // POST /domains/:id/merge
+
+		// Delete
+		r.Delete("/{id}", handler.deleteDomain) // DELETE /domains/:id
+	})
+}
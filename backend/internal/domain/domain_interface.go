@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type DomainRepositoryInterface interface {
+	Create(ctx context.Context, domain *Domain) error
+	GetAll(ctx context.Context, includeArchived bool) ([]*Domain, error)
+	GetById(ctx context.Context, domainid uuid.UUID) (*Domain, error)
+	Update(ctx context.Context, domain *Domain) error
+	Delete(ctx context.Context, domainid uuid.UUID) error
+	Reorder(ctx context.Context, domainids []uuid.UUID) error
+	Merge(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error
+}
+
+type DomainServiceInterface interface {
+	CreateDomain(ctx context.Context, domain *Domain) error
+	GetAllDomains(ctx context.Context, includeArchived bool) ([]*Domain, error)
+	GetDomainById(ctx context.Context, domainid uuid.UUID) (*Domain, error)
+	UpdateDomain(ctx context.Context, domain *Domain) error
+	DeleteDomain(ctx context.Context, domainid uuid.UUID) error
+	ReorderDomains(ctx context.Context, domainids []uuid.UUID) error
+	MergeDomains(ctx context.Context, source *Domain, target *Domain) error
+}
@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres error codes for violated unique and foreign key constraints
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// domainSelect selects every domain of the user passed as $1 together with
+// the number of tasks filed under it
+const domainSelect = `SELECT d.domain_id, d.slug, d.display_name, d.color, d.icon, d.position, d.archived, d.created_at, d.updated_at,
+	(SELECT COUNT(*) FROM tasks t WHERE t.domain = d.slug AND t.owner_id = $1) AS task_count
+	FROM domains d WHERE d.owner_id = $1`
+
+// compactPositions closes gaps in the positions left behind by deleted
+// domains of the user passed as $1
+const compactPositions = `UPDATE domains d SET position = ordered.new_position
+	FROM (SELECT domain_id, ROW_NUMBER() OVER (ORDER BY position, slug) - 1 AS new_position FROM domains WHERE owner_id = $1) ordered
+	WHERE d.domain_id = ordered.domain_id AND d.position <> ordered.new_position`
+
+type DomainRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDomainRepo(db *pgxpool.Pool) *DomainRepo {
+	return &DomainRepo{db: db}
+}
+
+func (r *DomainRepo) Create(ctx context.Context, domain *Domain) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// New domains are appended behind the user's current last position
+	query := `INSERT INTO domains (slug, display_name, color, icon, position, archived, owner_id) VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM domains WHERE owner_id = $6), $5, $6) RETURNING domain_id, position, created_at, updated_at`
+	err = r.db.QueryRow(ctx, query,
+		domain.Slug,
+		domain.DisplayName,
+		domain.Color,
+		domain.Icon,
+		domain.Archived,
+		ownerId,
+	).Scan(&domain.DomainId, &domain.Position, &domain.CreatedAt, &domain.UpdatedAt)
+	if err != nil {
+		if isViolation(err, pgUniqueViolation) {
+			return errorutils.ErrDomainSlugTaken
+		}
+		return fmt.Errorf("failed to create domain: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DomainRepo) GetAll(ctx context.Context, includeArchived bool) ([]*Domain, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := domainSelect
+	if !includeArchived {
+		query += ` AND NOT d.archived`
+	}
+	query += ` ORDER BY d.position, d.slug`
+
+	rows, err := r.db.Query(ctx, query, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query domains: %w", err)
+	}
+	defer rows.Close()
+
+	domains := make([]*Domain, 0)
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan domain: %w", err)
+		}
+		domains = append(domains, domain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return domains, nil
+}
+
+func (r *DomainRepo) GetById(ctx context.Context, domainid uuid.UUID) (*Domain, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := domainSelect + ` AND d.domain_id = $2`
+	domain, err := scanDomain(r.db.QueryRow(ctx, query, ownerId, domainid))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrDomainNotFound
+		}
+		return nil, fmt.Errorf("failed to get domain by id: %w", err)
+	}
+	return domain, nil
+}
+
+func (r *DomainRepo) Update(ctx context.Context, domain *Domain) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A new slug cascades to the tasks filed under the domain
+	query := `UPDATE domains SET slug=$1, display_name=$2, color=$3, icon=$4, archived=$5, updated_at=NOW() WHERE domain_id=$6 AND owner_id=$7 RETURNING updated_at`
+	err = r.db.QueryRow(ctx, query,
+		domain.Slug,
+		domain.DisplayName,
+		domain.Color,
+		domain.Icon,
+		domain.Archived,
+		domain.DomainId,
+		ownerId,
+	).Scan(&domain.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrDomainNotFound
+		}
+		if isViolation(err, pgUniqueViolation) {
+			return errorutils.ErrDomainSlugTaken
+		}
+		return fmt.Errorf("failed to update domain: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DomainRepo) Delete(ctx context.Context, domainid uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Tasks still referencing the slug make the delete fail
+	tag, err := tx.Exec(ctx, `DELETE FROM domains WHERE domain_id = $1 AND owner_id = $2`, domainid, ownerId)
+	if err != nil {
+		if isViolation(err, pgForeignKeyViolation) {
+			return errorutils.ErrDomainInUse
+		}
+		return fmt.Errorf("failed to delete domain: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrDomainNotFound
+	}
+
+	_, err = tx.Exec(ctx, compactPositions, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to compact domain positions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *DomainRepo) Reorder(ctx context.Context, domainids []uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Lock the table so concurrent creates or reorders can't interleave
+	_, err = tx.Exec(ctx, `LOCK TABLE domains IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return fmt.Errorf("failed to lock domains: %w", err)
+	}
+
+	var total int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM domains WHERE owner_id = $1`, ownerId).Scan(&total)
+	if err != nil {
+		return fmt.Errorf("failed to count domains: %w", err)
+	}
+	if total != len(domainids) {
+		return errorutils.ErrInvalidDomainReorder
+	}
+
+	// Position of every domain is its index in domainids
+	query := `UPDATE domains d SET position = ordered.idx - 1, updated_at = NOW()
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(id, idx)
+		WHERE d.domain_id = ordered.id AND d.owner_id = $2`
+	tag, err := tx.Exec(ctx, query, domainids, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to reorder domains: %w", err)
+	}
+	if int(tag.RowsAffected()) != total {
+		return errorutils.ErrInvalidDomainReorder
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *DomainRepo) Merge(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `LOCK TABLE domains IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return fmt.Errorf("failed to lock domains: %w", err)
+	}
+
+	// The target may have been deleted since the service checked it
+	var targetSlug string
+	var targetArchived bool
+	err = tx.QueryRow(ctx, `SELECT slug, archived FROM domains WHERE domain_id = $1 AND owner_id = $2 FOR SHARE`, targetid, ownerId).Scan(&targetSlug, &targetArchived)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrDomainNotFound
+		}
+		return fmt.Errorf("failed to lock target domain: %w", err)
+	}
+	if targetArchived {
+		return errorutils.ErrArchivedDomain
+	}
+
+	// Move the tasks over first so deleting the source can't hit the foreign key
+	query := `UPDATE tasks SET domain = $2, version = tasks.version + 1
+		FROM domains source
+		WHERE source.domain_id = $1 AND source.owner_id = $3 AND tasks.domain = source.slug AND tasks.owner_id = $3`
+	_, err = tx.Exec(ctx, query, sourceid, targetSlug, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to move tasks to target domain: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM domains WHERE domain_id = $1 AND owner_id = $2`, sourceid, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete merged domain: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrDomainNotFound
+	}
+
+	_, err = tx.Exec(ctx, compactPositions, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to compact domain positions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// scanDomain scans a row selected with domainSelect into a Domain
+func scanDomain(row pgx.Row) (*Domain, error) {
+	var domain Domain
+	err := row.Scan(
+		&domain.DomainId,
+		&domain.Slug,
+		&domain.DisplayName,
+		&domain.Color,
+		&domain.Icon,
+		&domain.Position,
+		&domain.Archived,
+		&domain.CreatedAt,
+		&domain.UpdatedAt,
+		&domain.TaskCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// isViolation reports whether err was caused by the Postgres error code
+func isViolation(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/database/dbtest"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+func TestDomainRepoIsolatesOwners(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewDomainRepo(db)
+	alice := dbtest.UserContext(t, db)
+	bob := dbtest.UserContext(t, db)
+
+	// Every user starts with their own copy of the default domains
+	defaults, err := repo.GetAll(bob, true)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(defaults) == 0 {
+		t.Fatal("new user has no default domains")
+	}
+
+	domain := &Domain{Slug: "garden", DisplayName: "Garden", Color: "#10B981"}
+	if err := repo.Create(alice, domain); err != nil {
+		t.Fatalf("failed to create domain: %v", err)
+	}
+
+	if _, err := repo.GetById(bob, domain.DomainId); !errors.Is(err, errorutils.ErrDomainNotFound) {
+		t.Errorf("GetById = %v, want ErrDomainNotFound", err)
+	}
+
+	changed := *domain
+	changed.DisplayName = "Taken over"
+	if err := repo.Update(bob, &changed); !errors.Is(err, errorutils.ErrDomainNotFound) {
+		t.Errorf("Update = %v, want ErrDomainNotFound", err)
+	}
+
+	if err := repo.Merge(bob, defaults[0].DomainId, domain.DomainId); !errors.Is(err, errorutils.ErrDomainNotFound) {
+		t.Errorf("Merge = %v, want ErrDomainNotFound", err)
+	}
+
+	if err := repo.Delete(bob, domain.DomainId); !errors.Is(err, errorutils.ErrDomainNotFound) {
+		t.Errorf("Delete = %v, want ErrDomainNotFound", err)
+	}
+
+	// Reordering may only name the user's own domains
+	ids := make([]uuid.UUID, len(defaults))
+	for i, own := range defaults {
+		ids[i] = own.DomainId
+	}
+	ids[0] = domain.DomainId
+	if err := repo.Reorder(bob, ids); !errors.Is(err, errorutils.ErrInvalidDomainReorder) {
+		t.Errorf("Reorder = %v, want ErrInvalidDomainReorder", err)
+	}
+
+	domains, err := repo.GetAll(bob, true)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	for _, listed := range domains {
+		if listed.DomainId == domain.DomainId {
+			t.Errorf("GetAll returned domain %s of another user", listed.DomainId)
+		}
+	}
+
+	// Alice's domain is untouched
+	current, err := repo.GetById(alice, domain.DomainId)
+	if err != nil {
+		t.Fatalf("GetById of the owner failed: %v", err)
+	}
+	if current.DisplayName != domain.DisplayName {
+		t.Errorf("domain was changed by another user: %+v", current)
+	}
+}
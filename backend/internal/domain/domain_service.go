@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+var (
+	// hexColorPattern matches short and long hex colors such as #0af or #00aaff
+	hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	// slugPattern matches lowercase words joined by single dashes such as deep-work
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+type DomainService struct {
+	repo DomainRepositoryInterface
+}
+
+func NewDomainService(repo DomainRepositoryInterface) *DomainService {
+	return &DomainService{repo: repo}
+}
+
+func (s *DomainService) CreateDomain(ctx context.Context, domain *Domain) error {
+	// Check for required fields
+	err := checkFields(domain)
+	if err != nil {
+		return err
+	}
+
+	// Create Domain
+	err = s.repo.Create(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("failed to create domain: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DomainService) GetAllDomains(ctx context.Context, includeArchived bool) ([]*Domain, error) {
+	domains, err := s.repo.GetAll(ctx, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all domains: %w", err)
+	}
+
+	return domains, nil
+}
+
+func (s *DomainService) GetDomainById(ctx context.Context, domainid uuid.UUID) (*Domain, error) {
+	// Check if id isn't empty
+	if domainid == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	domain, err := s.repo.GetById(ctx, domainid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain by id: %w", err)
+	}
+
+	return domain, nil
+}
+
+func (s *DomainService) UpdateDomain(ctx context.Context, domain *Domain) error {
+	// Check if id isn't empty
+	if domain.DomainId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Check for required fields
+	err := checkFields(domain)
+	if err != nil {
+		return err
+	}
+
+	// Update Domain
+	err = s.repo.Update(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("failed to update domain: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDomain deletes a domain no task is filed under, used domains have to be merged or archived
+func (s *DomainService) DeleteDomain(ctx context.Context, domainid uuid.UUID) error {
+	// Check if id isn't empty
+	if domainid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.Delete(ctx, domainid)
+	if err != nil {
+		return fmt.Errorf("failed to delete domain: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DomainService) ReorderDomains(ctx context.Context, domainids []uuid.UUID) error {
+	// Every domain may only appear once in the new order
+	seen := make(map[uuid.UUID]bool, len(domainids))
+	for _, id := range domainids {
+		if id == uuid.Nil || seen[id] {
+			return errorutils.ErrInvalidDomainReorder
+		}
+		seen[id] = true
+	}
+
+	err := s.repo.Reorder(ctx, domainids)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidDomainReorder) {
+			return err
+		}
+		return fmt.Errorf("failed to reorder domains: %w", err)
+	}
+
+	return nil
+}
+
+// MergeDomains refiles every task of source under target and deletes source
+func (s *DomainService) MergeDomains(ctx context.Context, source *Domain, target *Domain) error {
+	if source.DomainId == target.DomainId {
+		return errorutils.ErrDomainMergeIntoSelf
+	}
+
+	// Tasks can't be moved into a domain that no longer takes new tasks
+	if target.Archived {
+		return errorutils.ErrArchivedDomain
+	}
+
+	err := s.repo.Merge(ctx, source.DomainId, target.DomainId)
+	if err != nil {
+		return fmt.Errorf("failed to merge domains: %w", err)
+	}
+
+	return nil
+}
+
+// checkFields trims the text fields and validates slug, display name and color
+func checkFields(domain *Domain) error {
+	domain.Slug = strings.TrimSpace(domain.Slug)
+	if !slugPattern.MatchString(domain.Slug) {
+		return errorutils.ErrInvalidSlug
+	}
+
+	domain.DisplayName = strings.TrimSpace(domain.DisplayName)
+	if domain.DisplayName == "" {
+		return errorutils.ErrDisplayNameRequired
+	}
+
+	if !hexColorPattern.MatchString(domain.Color) {
+		return errorutils.ErrInvalidColor
+	}
+
+	domain.Icon = strings.TrimSpace(domain.Icon)
+	return nil
+}
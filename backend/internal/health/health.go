@@ -0,0 +1,187 @@
+// Package health serves the liveness and readiness checks.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// requiredTables are the tables the repositories query
+var requiredTables = []string{
+	"tasks", "task_recurrences", "task_checklist_items", "task_dependencies",
+	"projects", "phases", "tech_stack_items", "project_tech_stack",
+	"tags", "task_tags", "project_tags", "domains", "default_domains",
+	"users", "refresh_tokens", "api_tokens",
+}
+
+// requiredEnums are the enum types the repositories cast to
+var requiredEnums = []string{
+	"priority_enum", "project_status_enum", "phase_status_enum", "recurrence_frequency_enum",
+}
+
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+type Checker struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+// NewChecker returns a Checker giving every check of a readiness probe timeout to finish
+func NewChecker(db *pgxpool.Pool, timeout time.Duration) *Checker {
+	return &Checker{db: db, timeout: timeout}
+}
+
+// Ready runs every check, the report fails if one of them does
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK}
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"database", c.checkDatabase},
+		{"tables", c.checkTables},
+		{"enums", c.checkEnums},
+	}
+
+	for _, check := range checks {
+		result := c.run(ctx, check.name, check.run)
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, name string, check func(ctx context.Context) error) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Name:      name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = publicError(err)
+		slog.WarnContext(ctx, "Health check failed", "check", name, "error", err)
+	}
+	return result
+}
+
+// publicError keeps connection details out of the public report, they are logged instead
+func publicError(err error) string {
+	var missing *missingError
+	if errors.As(err, &missing) {
+		return missing.Error()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return "check failed"
+}
+
+func (c *Checker) checkDatabase(ctx context.Context) error {
+	return c.db.Ping(ctx)
+}
+
+func (c *Checker) checkTables(ctx context.Context) error {
+	query := `SELECT required.name FROM unnest($1::text[]) AS required(name)
+		WHERE to_regclass(required.name) IS NULL`
+	return c.checkMissing(ctx, "tables", query, requiredTables)
+}
+
+func (c *Checker) checkEnums(ctx context.Context) error {
+	query := `SELECT required.name FROM unnest($1::text[]) AS required(name)
+		WHERE NOT EXISTS (SELECT 1 FROM pg_type t WHERE t.typname = required.name AND t.typtype = 'e')`
+	return c.checkMissing(ctx, "enum types", query, requiredEnums)
+}
+
+// checkMissing runs a query returning the names it couldn't find
+func (c *Checker) checkMissing(ctx context.Context, kind string, query string, names []string) error {
+	rows, err := c.db.Query(ctx, query, names)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", kind, err)
+	}
+	defer rows.Close()
+
+	missing := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", kind, err)
+		}
+		missing = append(missing, name)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(missing) > 0 {
+		return &missingError{kind: kind, names: missing}
+	}
+	return nil
+}
+
+// missingError names the tables or types the schema lacks
+type missingError struct {
+	kind  string
+	names []string
+}
+
+func (e *missingError) Error() string {
+	return fmt.Sprintf("missing %s: %s", e.kind, strings.Join(e.names, ", "))
+}
+
+// live handles GET /health/live, it only shows the process is serving requests
+func live(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// ready handles GET /health/ready, answering 503 while a check fails so load
+// balancers stop routing to the instance
+func (c *Checker) ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	utils.RespondWithJSON(w, status, report)
+}
+
+// RegisterRoutes registers the health routes. /health stays as an alias of
+// /health/live for existing probes.
+func RegisterRoutes(r chi.Router, checker *Checker) {
+	r.Get("/health", live)                // GET /health
+	r.Get("/health/live", live)           // GET /health/live
+	r.Get("/health/ready", checker.ready) // GET /health/ready
+}
@@ -0,0 +1,401 @@
+// Package migrate applies the numbered SQL migrations and tracks them in the schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey identifies the advisory lock that keeps two instances from migrating at once
+const lockKey int64 = 0x6a6f6b6572 // "joker"
+
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApplied  State = "applied"
+	StateModified State = "modified" // applied, but the up script changed since
+	StateMissing  State = "missing"  // applied, but this binary has no script for it
+)
+
+type Status struct {
+	Version   int
+	Name      string
+	State     State
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Hook prepares the data for a migration. It runs in the migration's
+// transaction right before the up script.
+type Hook func(ctx context.Context, tx pgx.Tx) error
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []*Migration
+	hooks      map[int]Hook
+}
+
+func NewMigrator(db *pgxpool.Pool, migrations []*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations, hooks: make(map[int]Hook)}
+}
+
+// BeforeUp registers hook to run before the up script of version
+func (m *Migrator) BeforeUp(version int, hook Hook) {
+	m.hooks[version] = hook
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down rolls back the most recently applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		latest := -1
+		for version := range applied {
+			latest = max(latest, version)
+		}
+		if latest < 0 {
+			slog.Info("Nothing to roll back")
+			return nil
+		}
+
+		migration, err := m.find(latest)
+		if err != nil {
+			return err
+		}
+		return m.run(ctx, conn, migration, false)
+	})
+}
+
+// To applies or rolls back migrations until exactly the versions up to target are applied
+func (m *Migrator) To(ctx context.Context, target int) error {
+	if target < 0 {
+		return fmt.Errorf("target version must not be negative")
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		// Fail before touching anything if a version to roll back has no script
+		for version := range applied {
+			if version > target {
+				if _, err := m.find(version); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Roll back from the newest applied version downwards
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; ok && migration.Version > target {
+				if err := m.run(ctx, conn, migration, false); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Then apply the pending ones in order
+		changed := false
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= target {
+				if err := m.run(ctx, conn, migration, true); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+
+		if !changed {
+			slog.Info("Database schema is up to date")
+		}
+		return nil
+	})
+}
+
+// Baseline records every migration up to version as applied without running it.
+// It is meant for databases that were migrated by hand before the runner existed.
+func (m *Migrator) Baseline(ctx context.Context, version int) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			return fmt.Errorf("schema_migrations already tracks %d migrations, baseline only works on an untracked database", len(applied))
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if err := record(ctx, conn, migration); err != nil {
+				return err
+			}
+			slog.Info("Marked migration as applied", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Status lists every known and every applied migration in version order
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name, State: StatePending}
+			if row, ok := applied[migration.Version]; ok {
+				status.State = StateApplied
+				if row.checksum != migration.Checksum {
+					status.State = StateModified
+				}
+				status.AppliedAt = &row.appliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		for version, row := range applied {
+			statuses = append(statuses, Status{Version: version, Name: row.name, State: StateMissing, AppliedAt: &row.appliedAt})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Session level lock, so it has to be released on the same connection
+	_, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey)
+	if err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureTable creates schema_migrations. A table of the same name left behind
+// by golang-migrate, which the production image used before, is converted.
+func (m *Migrator) ensureTable(ctx context.Context, conn *pgxpool.Conn) error {
+	var legacy bool
+	query := `SELECT EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'schema_migrations' AND column_name = 'dirty')`
+	err := conn.QueryRow(ctx, query).Scan(&legacy)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema_migrations: %w", err)
+	}
+	if legacy {
+		return m.convertLegacyTable(ctx, conn)
+	}
+
+	_, err = conn.Exec(ctx, createTable)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+func (m *Migrator) convertLegacyTable(ctx context.Context, conn *pgxpool.Conn) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// golang-migrate keeps a single row with the current version
+	var version int
+	var dirty bool
+	err = tx.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to read legacy schema_migrations: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("legacy schema_migrations is dirty at version %d, repair the database first", version)
+	}
+
+	for _, query := range []string{`DROP TABLE schema_migrations`, createTable} {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return fmt.Errorf("failed to replace legacy schema_migrations: %w", err)
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		if err := record(ctx, tx, migration); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("Converted legacy schema_migrations", "version", version)
+	return tx.Commit(ctx)
+}
+
+// verify refuses to continue if an applied migration was edited afterwards
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		if ok && row.checksum != migration.Checksum {
+			return fmt.Errorf("migration %03d_%s was changed after it was applied", migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) (*Migration, error) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, nil
+		}
+	}
+	return nil, fmt.Errorf("migration %d is applied but unknown to this binary", version)
+}
+
+// run applies or rolls back one migration together with its bookkeeping in a single transaction
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration *Migration, up bool) error {
+	script := migration.Up
+	if !up {
+		script = migration.Down
+		if script == "" {
+			return fmt.Errorf("migration %03d_%s has no down script", migration.Version, migration.Name)
+		}
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	if hook, ok := m.hooks[migration.Version]; ok && up {
+		if err := hook(ctx, tx); err != nil {
+			return fmt.Errorf("migration %03d_%s failed to prepare: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	// Without arguments pgx uses the simple protocol, which allows several statements per script
+	_, err = tx.Exec(ctx, script)
+	if err != nil {
+		return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		err = record(ctx, tx, migration)
+	} else {
+		_, err = tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to track migration %d: %w", migration.Version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", migration.Version, err)
+	}
+
+	if up {
+		slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	} else {
+		slog.Info("Rolled back migration", "version", migration.Version, "name", migration.Name)
+	}
+	return nil
+}
+
+// execer is implemented by both connections and transactions
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// record marks the migration as applied
+func record(ctx context.Context, db execer, migration *Migration) error {
+	_, err := db.Exec(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func loadApplied(ctx context.Context, conn *pgxpool.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return applied, nil
+}
@@ -27,10 +27,17 @@ type Project struct {
 	Description  string      `json:"description" db:"description"`
 	Status       Status      `json:"status" db:"status"`
 	TechStackIds []uuid.UUID `json:"tech_stack_ids" db:"-"`
+	TagIds       []uuid.UUID `json:"tag_ids" db:"-"`
 	GithubUrl    *string     `json:"github_url,omitempty" db:"github_url"`
 	LiveUrl      *string     `json:"live_url,omitempty" db:"live_url"`
 	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+	Version      int         `json:"version" db:"version"`
+}
+
+// ProjectFilter narrows the project list, projects have to carry every listed tag
+type ProjectFilter struct {
+	TagIds []uuid.UUID
 }
 
 type TechStackItem struct {
@@ -38,6 +45,7 @@ type TechStackItem struct {
 	Name            string    `json:"name" db:"name"`
 	Color           string    `json:"color" db:"color"`
 	Position        int       `json:"position" db:"position"`
+	UsageCount      int       `json:"usage_count" db:"-"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -52,3 +60,41 @@ type Phase struct {
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// StalledAfterDays is the number of days without task activity after which an active project counts as stalled
+const StalledAfterDays = 14
+
+// Maximum lengths of the text fields, in characters
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 5000
+	MaxNameLength        = 50
+	MaxURLLength         = 2048
+)
+
+type TaskCounts struct {
+	Open      int `json:"open"`
+	Completed int `json:"completed"`
+	Overdue   int `json:"overdue"`
+}
+
+// PhaseSummary holds the task rollup of one phase. Tasks of the project
+// without a phase are summarized in an entry with a nil PhaseId.
+type PhaseSummary struct {
+	TaskCounts
+	PhaseId        *uuid.UUID `json:"phase_id"`
+	Title          string     `json:"title"`
+	NextDeadline   *time.Time `json:"next_deadline,omitempty"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+}
+
+type ProjectSummary struct {
+	TaskCounts
+	ProjectId             uuid.UUID       `json:"project_id"`
+	PercentComplete       float64         `json:"percent_complete"`
+	NextDeadline          *time.Time      `json:"next_deadline,omitempty"`
+	LastActivityAt        *time.Time      `json:"last_activity_at,omitempty"`
+	DaysSinceLastActivity *int            `json:"days_since_last_activity,omitempty"`
+	Stalled               bool            `json:"stalled"`
+	Phases                []*PhaseSummary `json:"phases,omitempty"`
+}
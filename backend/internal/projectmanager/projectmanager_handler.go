@@ -2,8 +2,10 @@ package projectmanager
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
 	"github.com/J0kerul/jokers-hub/pkg/utils"
@@ -15,21 +17,112 @@ type CreateProjectRequest struct {
 	Title       string      `json:"title" validate:"required"`
 	Description string      `json:"description" validate:"required"`
 	TechStack   []uuid.UUID `json:"tech_stack_ids" validate:"required"`
+	TagIds      []uuid.UUID `json:"tag_ids,omitempty"`
 	Status      Status      `json:"status" validate:"required"`
 	GithubUrl   *string     `json:"github_url,omitempty"`
 	LiveUrl     *string     `json:"live_url,omitempty"`
 }
 
+type UpdateProjectRequest struct {
+	Title       *string      `json:"title,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	TechStack   *[]uuid.UUID `json:"tech_stack_ids,omitempty"`
+	TagIds      *[]uuid.UUID `json:"tag_ids,omitempty"`
+	Status      *Status      `json:"status,omitempty"`
+	GithubUrl   *string      `json:"github_url,omitempty"`
+	LiveUrl     *string      `json:"live_url,omitempty"`
+}
+
 type ProjectResponse struct {
 	ProjectId   uuid.UUID   `json:"project_id"`
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	TechStack   []uuid.UUID `json:"tech_stack_ids"`
+	TagIds      []uuid.UUID `json:"tag_ids"`
 	Status      Status      `json:"status"`
 	GithubUrl   *string     `json:"github_url,omitempty"`
 	LiveUrl     *string     `json:"live_url,omitempty"`
 	CreatedAt   string      `json:"created_at"`
 	UpdatedAt   string      `json:"updated_at"`
+	Version     int         `json:"version"`
+
+	Summary *ProjectSummaryResponse `json:"summary,omitempty"`
+}
+
+type TaskCountsResponse struct {
+	Open      int `json:"open"`
+	Completed int `json:"completed"`
+	Overdue   int `json:"overdue"`
+}
+
+type PhaseSummaryResponse struct {
+	PhaseId        *uuid.UUID         `json:"phase_id"`
+	Title          string             `json:"title"`
+	Tasks          TaskCountsResponse `json:"tasks"`
+	NextDeadline   *string            `json:"next_deadline,omitempty"`
+	LastActivityAt *string            `json:"last_activity_at,omitempty"`
+}
+
+type ProjectSummaryResponse struct {
+	ProjectId             uuid.UUID               `json:"project_id"`
+	Tasks                 TaskCountsResponse      `json:"tasks"`
+	PercentComplete       float64                 `json:"percent_complete"`
+	NextDeadline          *string                 `json:"next_deadline,omitempty"`
+	LastActivityAt        *string                 `json:"last_activity_at,omitempty"`
+	DaysSinceLastActivity *int                    `json:"days_since_last_activity,omitempty"`
+	Stalled               bool                    `json:"stalled"`
+	Phases                []*PhaseSummaryResponse `json:"phases,omitempty"`
+}
+
+type CreateTechStackItemRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type UpdateTechStackItemRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+type ReorderTechStackRequest struct {
+	TechStackItemIds []uuid.UUID `json:"tech_stack_item_ids"`
+}
+
+type TechStackItemResponse struct {
+	TechStackItemId uuid.UUID `json:"tech_stack_item_id"`
+	Name            string    `json:"name"`
+	Color           string    `json:"color"`
+	Position        int       `json:"position"`
+	UsageCount      int       `json:"usage_count"`
+	CreatedAt       string    `json:"created_at"`
+	UpdatedAt       string    `json:"updated_at"`
+}
+
+type CreatePhaseRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      Status `json:"status"`
+}
+
+type UpdatePhaseRequest struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *Status `json:"status,omitempty"`
+}
+
+type ReorderPhasesRequest struct {
+	PhaseIds []uuid.UUID `json:"phase_ids"`
+}
+
+type PhaseResponse struct {
+	PhaseId     uuid.UUID `json:"phase_id"`
+	ProjectId   uuid.UUID `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      Status    `json:"status"`
+	Position    int       `json:"position"`
+	CreatedAt   string    `json:"created_at"`
+	UpdatedAt   string    `json:"updated_at"`
 }
 
 type ProjectManagerHandler struct {
@@ -60,16 +153,13 @@ func (h *ProjectManagerHandler) createProject(w http.ResponseWriter, r *http.Req
 		GithubUrl:    req.GithubUrl,
 		LiveUrl:      req.LiveUrl,
 		TechStackIds: req.TechStack,
+		TagIds:       req.TagIds,
 	}
 
 	// 3. Call service to create project
 	err := h.ProjectManagerService.CreateProjectSrc(r.Context(), project)
 	if err != nil {
-		if isValidationError(err) {
-			utils.RespondWithBadRequest(w, err.Error())
-			return
-		}
-		utils.RespondWithInternalError(w, fmt.Sprintf("Failed to create project: %v", err))
+		utils.RespondWithProblem(w, r, err, "Failed to create project")
 		return
 	}
 
@@ -77,19 +167,626 @@ func (h *ProjectManagerHandler) createProject(w http.ResponseWriter, r *http.Req
 	resp := projectToResponse(project)
 
 	// 5. Send response
+	utils.SetETag(w, project.Version)
 	utils.RespondWithJSON(w, http.StatusCreated, resp)
 }
 
-func isValidationError(err error) bool {
-	switch err {
-	case errorutils.ErrTitleRequired,
-		errorutils.ErrMissingDescription,
-		errorutils.ErrInvalidStatus,
-		errorutils.ErrNoTechStackItems:
-		return true
-	default:
+// getAllProjects handles GET /projects, ?include=summary embeds the task rollup of every project
+// and every ?tag_id narrows the list to projects carrying that tag
+func (h *ProjectManagerHandler) getAllProjects(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse tag filter
+	var filter ProjectFilter
+	for _, v := range r.URL.Query()["tag_id"] {
+		tagId, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondWithBadRequest(w, "Invalid tag_id")
+			return
+		}
+		filter.TagIds = append(filter.TagIds, tagId)
+	}
+
+	// 2. Call service to get all projects
+	projects, err := h.ProjectManagerService.GetAllProjectsSrc(r.Context(), filter)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve projects")
+		return
+	}
+
+	// 3. Optionally get the rollups of all projects at once
+	var summaries map[uuid.UUID]*ProjectSummary
+	if r.URL.Query().Get("include") == "summary" {
+		summaries, err = h.ProjectManagerService.GetProjectSummariesSrc(r.Context(), projects)
+		if err != nil {
+			utils.RespondWithProblem(w, r, err, "Failed to retrieve project summaries")
+			return
+		}
+	}
+
+	// 4. Entity -> Response DTOs
+	resp := make([]*ProjectResponse, len(projects))
+	for i, project := range projects {
+		resp[i] = projectToResponse(project)
+		if summary, ok := summaries[project.ProjectId]; ok {
+			resp[i].Summary = summaryToResponse(summary)
+		}
+	}
+
+	// 5. Send response
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectManagerHandler) getProjectById(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Call service to get project
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+
+	// 3. Entity -> Response DTO & send response
+	utils.SetETag(w, project.Version)
+	utils.RespondWithJSON(w, http.StatusOK, projectToResponse(project))
+}
+
+func (h *ProjectManagerHandler) getProjectSummary(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Get project, its status decides whether it can be stalled
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+
+	// 3. Call service to build the summary
+	summary, err := h.ProjectManagerService.GetProjectSummarySrc(r.Context(), project)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve project summary")
+		return
+	}
+
+	// 4. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusOK, summaryToResponse(summary))
+}
+
+func (h *ProjectManagerHandler) updateProject(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Parse request body
+	var req UpdateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get existing project and check it is the version the client edited
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+	if !ifMatchProject(w, r, project) {
+		return
+	}
+
+	// 4. Update fields
+	if req.Title != nil {
+		project.Title = *req.Title
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.TechStack != nil {
+		project.TechStackIds = *req.TechStack
+	}
+	if req.TagIds != nil {
+		project.TagIds = *req.TagIds
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if req.GithubUrl != nil {
+		project.GithubUrl = req.GithubUrl
+	}
+	if req.LiveUrl != nil {
+		project.LiveUrl = req.LiveUrl
+	}
+
+	// 5. Call service to update project
+	err = h.ProjectManagerService.UpdateProjectSrc(r.Context(), project)
+	if err != nil {
+		h.respondWithProjectWriteError(w, r, projectId, err, "Failed to update project")
+		return
+	}
+
+	// 6. Entity -> Response DTO & send response
+	utils.SetETag(w, project.Version)
+	utils.RespondWithJSON(w, http.StatusOK, projectToResponse(project))
+}
+
+// patchProject handles PATCH /projects/:id with a JSON Merge Patch (RFC 7396)
+// of the fields of UpdateProjectRequest, null clears github_url and live_url
+func (h *ProjectManagerHandler) patchProject(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Read patch
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get existing project and check it is the version the client edited
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+	if !ifMatchProject(w, r, project) {
+		return
+	}
+
+	// 4. Merge patch into the current fields
+	var req UpdateProjectRequest
+	if err := utils.DecodeMergePatch(projectToDocument(project), patch, &req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid merge patch")
+		return
+	}
+
+	// 5. Replace every field, the service validates the result
+	project.Title = utils.ValueOf(req.Title)
+	project.Description = utils.ValueOf(req.Description)
+	project.TechStackIds = utils.ValueOf(req.TechStack)
+	project.TagIds = utils.ValueOf(req.TagIds)
+	project.Status = utils.ValueOf(req.Status)
+	project.GithubUrl = req.GithubUrl
+	project.LiveUrl = req.LiveUrl
+
+	// 6. Call service to update project
+	err = h.ProjectManagerService.UpdateProjectSrc(r.Context(), project)
+	if err != nil {
+		h.respondWithProjectWriteError(w, r, projectId, err, "Failed to update project")
+		return
+	}
+
+	// 7. Entity -> Response DTO & send response
+	utils.SetETag(w, project.Version)
+	utils.RespondWithJSON(w, http.StatusOK, projectToResponse(project))
+}
+
+func (h *ProjectManagerHandler) deleteProject(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Get project and check it is the version the client saw
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+	if !ifMatchProject(w, r, project) {
+		return
+	}
+
+	// 3. Call service to delete project
+	err = h.ProjectManagerService.DeleteProjectSrc(r.Context(), projectId, project.Version)
+	if err != nil {
+		h.respondWithProjectWriteError(w, r, projectId, err, "Failed to delete project")
+		return
+	}
+
+	// 4. Send no content response
+	utils.RespondWithNoContent(w)
+}
+
+func (h *ProjectManagerHandler) createTechStackItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse request body
+	var req CreateTechStackItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. DTO -> Entity
+	item := &TechStackItem{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+
+	// 3. Call service to create tech stack item
+	err := h.ProjectManagerService.CreateTechStackItemSrc(r.Context(), item)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to create tech stack item")
+		return
+	}
+
+	// 4. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusCreated, techStackItemToResponse(item))
+}
+
+func (h *ProjectManagerHandler) getAllTechStackItems(w http.ResponseWriter, r *http.Request) {
+	// 1. Call service to get all tech stack items
+	items, err := h.ProjectManagerService.GetAllTechStackItemsSrc(r.Context())
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve tech stack items")
+		return
+	}
+
+	// 2. Entity -> Response DTOs
+	resp := make([]*TechStackItemResponse, len(items))
+	for i, item := range items {
+		resp[i] = techStackItemToResponse(item)
+	}
+
+	// 3. Send response
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectManagerHandler) getTechStackItemById(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	itemId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tech stack item ID")
+		return
+	}
+
+	// 2. Call service to get tech stack item
+	item, err := h.ProjectManagerService.GetTechStackItemByIdSrc(r.Context(), itemId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get tech stack item")
+		return
+	}
+
+	// 3. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusOK, techStackItemToResponse(item))
+}
+
+func (h *ProjectManagerHandler) updateTechStackItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	itemId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tech stack item ID")
+		return
+	}
+
+	// 2. Parse request body
+	var req UpdateTechStackItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get existing tech stack item
+	item, err := h.ProjectManagerService.GetTechStackItemByIdSrc(r.Context(), itemId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get tech stack item")
+		return
+	}
+
+	// 4. Update fields
+	if req.Name != nil {
+		item.Name = *req.Name
+	}
+	if req.Color != nil {
+		item.Color = *req.Color
+	}
+
+	// 5. Call service to update tech stack item
+	err = h.ProjectManagerService.UpdateTechStackItemSrc(r.Context(), item)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to update tech stack item")
+		return
+	}
+
+	// 6. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusOK, techStackItemToResponse(item))
+}
+
+func (h *ProjectManagerHandler) deleteTechStackItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	itemId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tech stack item ID")
+		return
+	}
+
+	// 2. Call service to delete tech stack item
+	err = h.ProjectManagerService.DeleteTechStackItemSrc(r.Context(), itemId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to delete tech stack item")
+		return
+	}
+
+	// 3. Send no content response
+	utils.RespondWithNoContent(w)
+}
+
+func (h *ProjectManagerHandler) reorderTechStackItems(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse request body
+	var req ReorderTechStackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. Call service to rewrite the positions
+	err := h.ProjectManagerService.ReorderTechStackItemsSrc(r.Context(), req.TechStackItemIds)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to reorder tech stack items")
+		return
+	}
+
+	// 3. Respond with the catalogue in its new order
+	h.getAllTechStackItems(w, r)
+}
+
+func (h *ProjectManagerHandler) createPhase(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse project ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Parse request body
+	var req CreatePhaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Make sure the project exists
+	if _, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId); err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+
+	// 4. DTO -> Entity
+	phase := &Phase{
+		ProjectId:   projectId,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+	}
+
+	// 5. Call service to create phase
+	err = h.ProjectManagerService.CreatePhaseSrc(r.Context(), phase)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to create phase")
+		return
+	}
+
+	// 6. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusCreated, phaseToResponse(phase))
+}
+
+func (h *ProjectManagerHandler) getPhasesByProject(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse project ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Make sure the project exists
+	if _, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId); err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+
+	// 3. Call service to get phases in order
+	phases, err := h.ProjectManagerService.GetPhasesByProjectSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve phases")
+		return
+	}
+
+	// 4. Entity -> Response DTOs
+	resp := make([]*PhaseResponse, len(phases))
+	for i, phase := range phases {
+		resp[i] = phaseToResponse(phase)
+	}
+
+	// 5. Send response
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectManagerHandler) getPhaseById(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	projectId, phaseId, ok := parsePhaseURL(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Call service to get phase
+	phase, err := h.ProjectManagerService.GetPhaseByIdSrc(r.Context(), projectId, phaseId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get phase")
+		return
+	}
+
+	// 3. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusOK, phaseToResponse(phase))
+}
+
+func (h *ProjectManagerHandler) updatePhase(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	projectId, phaseId, ok := parsePhaseURL(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Parse request body
+	var req UpdatePhaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get existing phase
+	phase, err := h.ProjectManagerService.GetPhaseByIdSrc(r.Context(), projectId, phaseId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get phase")
+		return
+	}
+
+	// 4. Update fields
+	if req.Title != nil {
+		phase.Title = *req.Title
+	}
+	if req.Description != nil {
+		phase.Description = *req.Description
+	}
+	if req.Status != nil {
+		phase.Status = *req.Status
+	}
+
+	// 5. Call service to update phase
+	err = h.ProjectManagerService.UpdatePhaseSrc(r.Context(), phase)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to update phase")
+		return
+	}
+
+	// 6. Entity -> Response DTO & send response
+	utils.RespondWithJSON(w, http.StatusOK, phaseToResponse(phase))
+}
+
+func (h *ProjectManagerHandler) deletePhase(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	projectId, phaseId, ok := parsePhaseURL(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Call service to delete phase
+	err := h.ProjectManagerService.DeletePhaseSrc(r.Context(), projectId, phaseId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to delete phase")
+		return
+	}
+
+	// 3. Send no content response
+	utils.RespondWithNoContent(w)
+}
+
+func (h *ProjectManagerHandler) reorderPhases(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse project ID from URL
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Parse request body
+	var req ReorderPhasesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Make sure the project exists
+	if _, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId); err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+
+	// 4. Call service to rewrite the positions
+	err = h.ProjectManagerService.ReorderPhasesSrc(r.Context(), projectId, req.PhaseIds)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to reorder phases")
+		return
+	}
+
+	// 5. Respond with the phases in their new order
+	h.getPhasesByProject(w, r)
+}
+
+// parsePhaseURL parses the project and phase ID from the URL and responds with 400 if one is invalid
+func parsePhaseURL(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	projectId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	phaseId, err := uuid.Parse(chi.URLParam(r, "phaseId"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid phase ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return projectId, phaseId, true
+}
+
+// ifMatchProject checks the If-Match header of r against the version of project.
+// Without the header it answers 428, on a mismatch 412 with the project, and
+// returns false.
+func ifMatchProject(w http.ResponseWriter, r *http.Request, project *Project) bool {
+	if !utils.HasIfMatch(r) {
+		utils.RespondWithProblem(w, r, errorutils.ErrIfMatchRequired, "If-Match is required")
 		return false
 	}
+	if utils.IfMatch(r, utils.ETag(project.Version)) {
+		return true
+	}
+	utils.RespondWithPreconditionFailed(w, project.Version, projectToResponse(project))
+	return false
+}
+
+// respondWithProjectWriteError answers err of a write to the project. When
+// the project changed concurrently the answer is 412 with the project as it is now.
+func (h *ProjectManagerHandler) respondWithProjectWriteError(w http.ResponseWriter, r *http.Request, projectId uuid.UUID, err error, message string) {
+	if !errors.Is(err, errorutils.ErrProjectModified) {
+		utils.RespondWithProblem(w, r, err, message)
+		return
+	}
+
+	project, err := h.ProjectManagerService.GetProjectByIdSrc(r.Context(), projectId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get project")
+		return
+	}
+	utils.RespondWithPreconditionFailed(w, project.Version, projectToResponse(project))
+}
+
+// projectToDocument returns the writable fields of project as the document a merge patch applies to
+func projectToDocument(project *Project) UpdateProjectRequest {
+	return UpdateProjectRequest{
+		Title:       &project.Title,
+		Description: &project.Description,
+		TechStack:   &project.TechStackIds,
+		TagIds:      &project.TagIds,
+		Status:      &project.Status,
+		GithubUrl:   project.GithubUrl,
+		LiveUrl:     project.LiveUrl,
+	}
 }
 
 func projectToResponse(project *Project) *ProjectResponse {
@@ -98,11 +795,70 @@ func projectToResponse(project *Project) *ProjectResponse {
 		Title:       project.Title,
 		Description: project.Description,
 		TechStack:   project.TechStackIds,
+		TagIds:      project.TagIds,
 		Status:      project.Status,
 		GithubUrl:   project.GithubUrl,
 		LiveUrl:     project.LiveUrl,
 		CreatedAt:   project.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:   project.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:     project.Version,
+	}
+}
+
+func summaryToResponse(summary *ProjectSummary) *ProjectSummaryResponse {
+	resp := &ProjectSummaryResponse{
+		ProjectId:             summary.ProjectId,
+		Tasks:                 TaskCountsResponse(summary.TaskCounts),
+		PercentComplete:       summary.PercentComplete,
+		NextDeadline:          formatOptionalTime(summary.NextDeadline),
+		LastActivityAt:        formatOptionalTime(summary.LastActivityAt),
+		DaysSinceLastActivity: summary.DaysSinceLastActivity,
+		Stalled:               summary.Stalled,
+	}
+
+	for _, phase := range summary.Phases {
+		resp.Phases = append(resp.Phases, &PhaseSummaryResponse{
+			PhaseId:        phase.PhaseId,
+			Title:          phase.Title,
+			Tasks:          TaskCountsResponse(phase.TaskCounts),
+			NextDeadline:   formatOptionalTime(phase.NextDeadline),
+			LastActivityAt: formatOptionalTime(phase.LastActivityAt),
+		})
+	}
+
+	return resp
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
+
+func phaseToResponse(phase *Phase) *PhaseResponse {
+	return &PhaseResponse{
+		PhaseId:     phase.PhaseId,
+		ProjectId:   phase.ProjectId,
+		Title:       phase.Title,
+		Description: phase.Description,
+		Status:      phase.Status,
+		Position:    phase.Position,
+		CreatedAt:   phase.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   phase.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func techStackItemToResponse(item *TechStackItem) *TechStackItemResponse {
+	return &TechStackItemResponse{
+		TechStackItemId: item.TechStackItemId,
+		Name:            item.Name,
+		Color:           item.Color,
+		Position:        item.Position,
+		UsageCount:      item.UsageCount,
+		CreatedAt:       item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:       item.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
@@ -110,5 +866,43 @@ func RegisterRoutes(r chi.Router, handler *ProjectManagerHandler) {
 	r.Route("/projects", func(r chi.Router) {
 		// Create Project
 		r.Post("/", handler.createProject)
+
+		// Get Projects
+		r.Get("/", handler.getAllProjects)
+		r.Get("/{id}", handler.getProjectById)
+		r.Get("/{id}/summary", handler.getProjectSummary)
+
+		// Update Project
+		r.Put("/{id}", handler.updateProject)
+		r.Patch("/{id}", handler.patchProject)
+
+		// Delete Project
+		r.Delete("/{id}", handler.deleteProject)
+
+		// Phases of a Project
+		r.Route("/{id}/phases", func(r chi.Router) {
+			r.Post("/", handler.createPhase)
+			r.Get("/", handler.getPhasesByProject)
+			r.Put("/reorder", handler.reorderPhases)
+			r.Get("/{phaseId}", handler.getPhaseById)
+			r.Put("/{phaseId}", handler.updatePhase)
+			r.Delete("/{phaseId}", handler.deletePhase)
+		})
+	})
+
+	r.Route("/tech-stack", func(r chi.Router) {
+		// Create Tech Stack Item
+		r.Post("/", handler.createTechStackItem)
+
+		// Get Tech Stack Items
+		r.Get("/", handler.getAllTechStackItems)
+		r.Get("/{id}", handler.getTechStackItemById)
+
+		// Update Tech Stack Items
+		r.Put("/reorder", handler.reorderTechStackItems)
+		r.Put("/{id}", handler.updateTechStackItem)
+
+		// Delete Tech Stack Item
+		r.Delete("/{id}", handler.deleteTechStackItem)
 	})
 }
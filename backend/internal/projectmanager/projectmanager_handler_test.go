@@ -0,0 +1,187 @@
+package projectmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// fakeProjectManagerService keeps projects in memory and fails every call
+// with err when it is set. Methods the tests don't need panic through the
+// nil interface.
+type fakeProjectManagerService struct {
+	ProjectManagerServiceInterface
+	projects map[uuid.UUID]*Project
+	err      error
+}
+
+func newFakeProjectManagerService(projects ...*Project) *fakeProjectManagerService {
+	service := &fakeProjectManagerService{projects: make(map[uuid.UUID]*Project)}
+	for _, project := range projects {
+		service.projects[project.ProjectId] = project
+	}
+	return service
+}
+
+func (s *fakeProjectManagerService) GetProjectByIdSrc(ctx context.Context, projectId uuid.UUID) (*Project, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	project, ok := s.projects[projectId]
+	if !ok {
+		return nil, errorutils.ErrProjectNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (s *fakeProjectManagerService) UpdateProjectSrc(ctx context.Context, project *Project) error {
+	current, ok := s.projects[project.ProjectId]
+	if !ok {
+		return errorutils.ErrProjectNotFound
+	}
+	if current.Version != project.Version {
+		return errorutils.ErrProjectModified
+	}
+	project.Version++
+	updated := *project
+	s.projects[project.ProjectId] = &updated
+	return nil
+}
+
+func (s *fakeProjectManagerService) DeleteProjectSrc(ctx context.Context, projectId uuid.UUID, version int) error {
+	project, ok := s.projects[projectId]
+	if !ok {
+		return errorutils.ErrProjectNotFound
+	}
+	if project.Version != version {
+		return errorutils.ErrProjectModified
+	}
+	delete(s.projects, projectId)
+	return nil
+}
+
+// serveProject sends the request through the project routes backed by service
+func serveProject(service ProjectManagerServiceInterface, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	RegisterRoutes(r, NewProjectManagerHandler(service))
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetProjectById(t *testing.T) {
+	project := &Project{ProjectId: uuid.New(), Title: "Hub", Status: StatusOngoing, Version: 4}
+
+	rec := serveProject(newFakeProjectManagerService(project), http.MethodGet, "/projects/"+project.ProjectId.String(), "", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"4"` {
+		t.Fatalf("ETag = %s, want %q", etag, `"4"`)
+	}
+}
+
+func TestGetProjectByIdMissing(t *testing.T) {
+	rec := serveProject(newFakeProjectManagerService(), http.MethodGet, "/projects/"+uuid.NewString(), "", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetProjectByIdServiceError(t *testing.T) {
+	service := newFakeProjectManagerService()
+	service.err = errors.New("connection refused")
+
+	rec := serveProject(service, http.MethodGet, "/projects/"+uuid.NewString(), "", nil)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestUpdateProjectStatus(t *testing.T) {
+	project := &Project{ProjectId: uuid.New(), Title: "Hub", Status: StatusOngoing, Version: 1}
+	service := newFakeProjectManagerService(project)
+
+	rec := serveProject(service, http.MethodPut, "/projects/"+project.ProjectId.String(), `{"status": "on_hold"}`, http.Header{"If-Match": {`"1"`}})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var response ProjectResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Status != StatusOnHold {
+		t.Fatalf("status = %s, want %s", response.Status, StatusOnHold)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"2"` {
+		t.Fatalf("ETag = %s, want %q", etag, `"2"`)
+	}
+}
+
+func TestUpdateProjectStaleVersion(t *testing.T) {
+	project := &Project{ProjectId: uuid.New(), Title: "Hub", Status: StatusOngoing, Version: 2}
+	service := newFakeProjectManagerService(project)
+
+	rec := serveProject(service, http.MethodPut, "/projects/"+project.ProjectId.String(), `{"status": "on_hold"}`, http.Header{"If-Match": {`"1"`}})
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+	if service.projects[project.ProjectId].Status != StatusOngoing {
+		t.Fatal("stale update changed the project")
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	project := &Project{ProjectId: uuid.New(), Title: "Hub", Status: StatusIdea, Version: 1}
+	service := newFakeProjectManagerService(project)
+
+	rec := serveProject(service, http.MethodDelete, "/projects/"+project.ProjectId.String(), "", http.Header{"If-Match": {`"1"`}})
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if _, ok := service.projects[project.ProjectId]; ok {
+		t.Fatal("project wasn't deleted")
+	}
+}
+
+func TestDeleteProjectWithoutIfMatch(t *testing.T) {
+	project := &Project{ProjectId: uuid.New(), Title: "Hub", Status: StatusIdea, Version: 1}
+	service := newFakeProjectManagerService(project)
+
+	rec := serveProject(service, http.MethodDelete, "/projects/"+project.ProjectId.String(), "", nil)
+
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionRequired)
+	}
+	if _, ok := service.projects[project.ProjectId]; !ok {
+		t.Fatal("delete without If-Match removed the project")
+	}
+}
+
+func TestDeleteProjectMissing(t *testing.T) {
+	rec := serveProject(newFakeProjectManagerService(), http.MethodDelete, "/projects/"+uuid.NewString(), "", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
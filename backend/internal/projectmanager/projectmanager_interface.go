@@ -1,11 +1,59 @@
 package projectmanager
 
-import "context"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 type ProjectManagerRepositoryInterface interface {
 	CreateProject(ctx context.Context, project *Project) error
+	GetAllProjects(ctx context.Context, filter ProjectFilter) ([]*Project, error)
+	GetProjectById(ctx context.Context, projectId uuid.UUID) (*Project, error)
+	UpdateProject(ctx context.Context, project *Project) error
+	DeleteProject(ctx context.Context, projectId uuid.UUID, version int) error
+
+	CreateTechStackItem(ctx context.Context, item *TechStackItem) error
+	GetAllTechStackItems(ctx context.Context) ([]*TechStackItem, error)
+	GetTechStackItemById(ctx context.Context, itemId uuid.UUID) (*TechStackItem, error)
+	UpdateTechStackItem(ctx context.Context, item *TechStackItem) error
+	DeleteTechStackItem(ctx context.Context, itemId uuid.UUID) error
+	ReorderTechStackItems(ctx context.Context, itemIds []uuid.UUID) error
+	FindUnknownTechStackIds(ctx context.Context, itemIds []uuid.UUID) ([]uuid.UUID, error)
+	FindUnknownTagIds(ctx context.Context, tagIds []uuid.UUID) ([]uuid.UUID, error)
+
+	CreatePhase(ctx context.Context, phase *Phase) error
+	GetPhasesByProject(ctx context.Context, projectId uuid.UUID) ([]*Phase, error)
+	GetPhaseById(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) (*Phase, error)
+	UpdatePhase(ctx context.Context, phase *Phase) error
+	DeletePhase(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) error
+	ReorderPhases(ctx context.Context, projectId uuid.UUID, phaseIds []uuid.UUID) error
+
+	GetPhaseSummaries(ctx context.Context, projectId uuid.UUID) ([]*PhaseSummary, error)
+	GetProjectSummaries(ctx context.Context) (map[uuid.UUID]*ProjectSummary, error)
 }
 
 type ProjectManagerServiceInterface interface {
 	CreateProjectSrc(ctx context.Context, project *Project) error
+	GetAllProjectsSrc(ctx context.Context, filter ProjectFilter) ([]*Project, error)
+	GetProjectByIdSrc(ctx context.Context, projectId uuid.UUID) (*Project, error)
+	UpdateProjectSrc(ctx context.Context, project *Project) error
+	DeleteProjectSrc(ctx context.Context, projectId uuid.UUID, version int) error
+
+	CreateTechStackItemSrc(ctx context.Context, item *TechStackItem) error
+	GetAllTechStackItemsSrc(ctx context.Context) ([]*TechStackItem, error)
+	GetTechStackItemByIdSrc(ctx context.Context, itemId uuid.UUID) (*TechStackItem, error)
+	UpdateTechStackItemSrc(ctx context.Context, item *TechStackItem) error
+	DeleteTechStackItemSrc(ctx context.Context, itemId uuid.UUID) error
+	ReorderTechStackItemsSrc(ctx context.Context, itemIds []uuid.UUID) error
+
+	CreatePhaseSrc(ctx context.Context, phase *Phase) error
+	GetPhasesByProjectSrc(ctx context.Context, projectId uuid.UUID) ([]*Phase, error)
+	GetPhaseByIdSrc(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) (*Phase, error)
+	UpdatePhaseSrc(ctx context.Context, phase *Phase) error
+	DeletePhaseSrc(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) error
+	ReorderPhasesSrc(ctx context.Context, projectId uuid.UUID, phaseIds []uuid.UUID) error
+
+	GetProjectSummarySrc(ctx context.Context, project *Project) (*ProjectSummary, error)
+	GetProjectSummariesSrc(ctx context.Context, projects []*Project) (map[uuid.UUID]*ProjectSummary, error)
 }
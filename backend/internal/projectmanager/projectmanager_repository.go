@@ -2,11 +2,47 @@ package projectmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	"github.com/J0kerul/jokers-hub/internal/database"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Postgres error codes for violated unique and foreign key constraints
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// projectSelect selects every project column together with its aggregated tech stack and tag ids
+const projectSelect = `SELECT p.project_id, p.title, p.description, p.status, p.github_url, p.live_url, p.created_at, p.updated_at, p.version,
+	COALESCE(array_agg(pts.tech_stack_item_id) FILTER (WHERE pts.tech_stack_item_id IS NOT NULL), '{}') AS tech_stack_ids,
+	ARRAY(SELECT pt.tag_id FROM project_tags pt WHERE pt.project_id = p.project_id) AS tag_ids
+	FROM projects p
+	LEFT JOIN project_tech_stack pts ON pts.project_id = p.project_id`
+
+// taskRollupColumns aggregates the tasks aliased as t into counts, next open deadline and last activity
+var taskRollupColumns = `COUNT(t.task_id) FILTER (WHERE NOT t.completed) AS open,
+	COUNT(t.task_id) FILTER (WHERE t.completed) AS completed,
+	COUNT(t.task_id) FILTER (WHERE ` + database.OverdueCondition("t") + `) AS overdue,
+	MIN(t.deadline) FILTER (WHERE NOT t.completed AND t.deadline >= CURRENT_DATE) AS next_deadline,
+	MAX(t.updated_at) AS last_activity`
+
+const phaseColumns = `phase_id, project_id, title, description, status, position, created_at, updated_at`
+
+// techStackSelect selects every tech stack column together with the number of projects using the item
+const techStackSelect = `SELECT t.tech_stack_item_id, t.name, t.color, t.position, t.created_at, t.updated_at,
+	COUNT(pts.project_id) AS usage_count
+	FROM tech_stack_items t
+	LEFT JOIN project_tech_stack pts ON pts.tech_stack_item_id = t.tech_stack_item_id`
+
 type ProjectManagerRepo struct {
 	db *pgxpool.Pool
 }
@@ -16,6 +52,11 @@ func NewProjectManagerRepo(db *pgxpool.Pool) *ProjectManagerRepo {
 }
 
 func (r *ProjectManagerRepo) CreateProject(ctx context.Context, project *Project) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -23,25 +64,850 @@ func (r *ProjectManagerRepo) CreateProject(ctx context.Context, project *Project
 
 	defer tx.Rollback(ctx)
 
-	query := `INSERT INTO projects (title, description, status, github_url, live_url) VALUES ($1, $2, $3, $4, $5) RETURNING project_id, created_at, updated_at`
+	query := `INSERT INTO projects (title, description, status, github_url, live_url, owner_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING project_id, created_at, updated_at, version`
 	err = tx.QueryRow(ctx, query,
 		project.Title,
 		project.Description,
 		project.Status,
 		project.GithubUrl,
 		project.LiveUrl,
-	).Scan(&project.ProjectId, &project.CreatedAt, &project.UpdatedAt)
+		ownerId,
+	).Scan(&project.ProjectId, &project.CreatedAt, &project.UpdatedAt, &project.Version)
 
 	if err != nil {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
+	err = insertTechStackLinks(ctx, tx, project)
+	if err != nil {
+		return err
+	}
+
+	err = insertTagLinks(ctx, tx, project)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) GetAllProjects(ctx context.Context, filter ProjectFilter) ([]*Project, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := projectSelect + ` WHERE p.owner_id = $1`
+	args := []any{ownerId}
+	if len(filter.TagIds) > 0 {
+		args = append(args, filter.TagIds)
+		query += ` AND (SELECT COUNT(*) FROM project_tags pt WHERE pt.project_id = p.project_id AND pt.tag_id = ANY($2)) = ` + strconv.Itoa(len(filter.TagIds))
+	}
+	query += ` GROUP BY p.project_id ORDER BY p.created_at DESC`
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make([]*Project, 0)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return projects, nil
+}
+
+func (r *ProjectManagerRepo) GetProjectById(ctx context.Context, projectId uuid.UUID) (*Project, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := projectSelect + ` WHERE p.project_id = $1 AND p.owner_id = $2 GROUP BY p.project_id`
+	project, err := scanProject(r.db.QueryRow(ctx, query, projectId, ownerId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project by id: %w", err)
+	}
+	return project, nil
+}
+
+func (r *ProjectManagerRepo) UpdateProject(ctx context.Context, project *Project) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The project has to be unchanged since it was read
+	err = lockProjectAt(ctx, tx, project.ProjectId, project.Version)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE projects SET title=$1, description=$2, status=$3, github_url=$4, live_url=$5, version=version+1, updated_at=NOW() WHERE project_id=$6 AND owner_id=$7 RETURNING updated_at, version`
+	err = tx.QueryRow(ctx, query,
+		project.Title,
+		project.Description,
+		project.Status,
+		project.GithubUrl,
+		project.LiveUrl,
+		project.ProjectId,
+		ownerId,
+	).Scan(&project.UpdatedAt, &project.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrProjectNotFound
+		}
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	// Replace the tech stack associations with the new set
+	_, err = tx.Exec(ctx, `DELETE FROM project_tech_stack WHERE project_id = $1`, project.ProjectId)
+	if err != nil {
+		return fmt.Errorf("failed to clear tech stack items of project: %w", err)
+	}
+
+	err = insertTechStackLinks(ctx, tx, project)
+	if err != nil {
+		return err
+	}
+
+	// Replace the tag associations with the new set
+	_, err = tx.Exec(ctx, `DELETE FROM project_tags WHERE project_id = $1`, project.ProjectId)
+	if err != nil {
+		return fmt.Errorf("failed to clear tags of project: %w", err)
+	}
+
+	err = insertTagLinks(ctx, tx, project)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) DeleteProject(ctx context.Context, projectId uuid.UUID, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The project has to be unchanged since it was read
+	err = lockProjectAt(ctx, tx, projectId, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM projects WHERE project_id = $1`, projectId)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) CreateTechStackItem(ctx context.Context, item *TechStackItem) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockTechStack(ctx, tx, ownerId)
+	if err != nil {
+		return err
+	}
+
+	// New items are appended behind the last position of the user's catalogue
+	query := `INSERT INTO tech_stack_items (name, color, owner_id, position) VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM tech_stack_items WHERE owner_id = $3)) RETURNING tech_stack_item_id, position, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		item.Name,
+		item.Color,
+		ownerId,
+	).Scan(&item.TechStackItemId, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errorutils.ErrTechStackNameTaken
+		}
+		return fmt.Errorf("failed to create tech stack item: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) GetAllTechStackItems(ctx context.Context) ([]*TechStackItem, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := techStackSelect + ` WHERE t.owner_id = $1 GROUP BY t.tech_stack_item_id ORDER BY t.position, t.name`
+	rows, err := r.db.Query(ctx, query, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tech stack items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*TechStackItem, 0)
+	for rows.Next() {
+		item, err := scanTechStackItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tech stack item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ProjectManagerRepo) GetTechStackItemById(ctx context.Context, itemId uuid.UUID) (*TechStackItem, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := techStackSelect + ` WHERE t.tech_stack_item_id = $1 AND t.owner_id = $2 GROUP BY t.tech_stack_item_id`
+	item, err := scanTechStackItem(r.db.QueryRow(ctx, query, itemId, ownerId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrTechStackItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get tech stack item by id: %w", err)
+	}
+	return item, nil
+}
+
+func (r *ProjectManagerRepo) UpdateTechStackItem(ctx context.Context, item *TechStackItem) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tech_stack_items SET name=$1, color=$2, updated_at=NOW() WHERE tech_stack_item_id=$3 AND owner_id=$4 RETURNING updated_at`
+	err = r.db.QueryRow(ctx, query,
+		item.Name,
+		item.Color,
+		item.TechStackItemId,
+		ownerId,
+	).Scan(&item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrTechStackItemNotFound
+		}
+		if isUniqueViolation(err) {
+			return errorutils.ErrTechStackNameTaken
+		}
+		return fmt.Errorf("failed to update tech stack item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProjectManagerRepo) DeleteTechStackItem(ctx context.Context, itemId uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockTechStack(ctx, tx, ownerId)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM tech_stack_items WHERE tech_stack_item_id = $1 AND owner_id = $2`, itemId, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete tech stack item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrTechStackItemNotFound
+	}
+
+	// Close the gap the deleted item left behind
+	query := `UPDATE tech_stack_items t SET position = ordered.new_position
+		FROM (SELECT tech_stack_item_id, ROW_NUMBER() OVER (ORDER BY position, name) - 1 AS new_position FROM tech_stack_items WHERE owner_id = $1) ordered
+		WHERE t.tech_stack_item_id = ordered.tech_stack_item_id AND t.position <> ordered.new_position`
+	_, err = tx.Exec(ctx, query, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to compact tech stack positions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) ReorderTechStackItems(ctx context.Context, itemIds []uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockTechStack(ctx, tx, ownerId)
+	if err != nil {
+		return err
+	}
+
+	var total int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM tech_stack_items WHERE owner_id = $1`, ownerId).Scan(&total)
+	if err != nil {
+		return fmt.Errorf("failed to count tech stack items: %w", err)
+	}
+	if total != len(itemIds) {
+		return errorutils.ErrInvalidReorder
+	}
+
+	// Position of every item is its index in itemIds
+	query := `UPDATE tech_stack_items t SET position = ordered.idx - 1, updated_at = NOW()
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(id, idx)
+		WHERE t.tech_stack_item_id = ordered.id AND t.owner_id = $2`
+	tag, err := tx.Exec(ctx, query, itemIds, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to reorder tech stack items: %w", err)
+	}
+	if int(tag.RowsAffected()) != total {
+		return errorutils.ErrInvalidReorder
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindUnknownTechStackIds returns the ids that aren't in the user's catalogue
+func (r *ProjectManagerRepo) FindUnknownTechStackIds(ctx context.Context, itemIds []uuid.UUID) ([]uuid.UUID, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id FROM unnest($1::uuid[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM tech_stack_items t WHERE t.tech_stack_item_id = id AND t.owner_id = $2)`
+	rows, err := r.db.Query(ctx, query, itemIds, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tech stack ids: %w", err)
+	}
+	defer rows.Close()
+
+	unknown := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tech stack id: %w", err)
+		}
+		unknown = append(unknown, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return unknown, nil
+}
+
+func (r *ProjectManagerRepo) FindUnknownTagIds(ctx context.Context, tagIds []uuid.UUID) ([]uuid.UUID, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id FROM unnest($1::uuid[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM tags g WHERE g.tag_id = id AND g.owner_id = $2)`
+	rows, err := r.db.Query(ctx, query, tagIds, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tag ids: %w", err)
+	}
+	defer rows.Close()
+
+	unknown := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tag id: %w", err)
+		}
+		unknown = append(unknown, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return unknown, nil
+}
+
+func (r *ProjectManagerRepo) CreatePhase(ctx context.Context, phase *Phase) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockProject(ctx, tx, phase.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	// New phases are appended behind the last phase of the project
+	query := `INSERT INTO phases (project_id, title, description, status, owner_id, position)
+		VALUES ($1, $2, $3, $4, $5, (SELECT COALESCE(MAX(position) + 1, 0) FROM phases WHERE project_id = $1))
+		RETURNING phase_id, position, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		phase.ProjectId,
+		phase.Title,
+		phase.Description,
+		phase.Status,
+		ownerId,
+	).Scan(&phase.PhaseId, &phase.Position, &phase.CreatedAt, &phase.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create phase: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) GetPhasesByProject(ctx context.Context, projectId uuid.UUID) ([]*Phase, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + phaseColumns + ` FROM phases WHERE project_id = $1 AND owner_id = $2 ORDER BY position`
+	rows, err := r.db.Query(ctx, query, projectId, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query phases: %w", err)
+	}
+	defer rows.Close()
+
+	phases := make([]*Phase, 0)
+	for rows.Next() {
+		phase, err := scanPhase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan phase: %w", err)
+		}
+		phases = append(phases, phase)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return phases, nil
+}
+
+func (r *ProjectManagerRepo) GetPhaseById(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) (*Phase, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + phaseColumns + ` FROM phases WHERE phase_id = $1 AND project_id = $2 AND owner_id = $3`
+	phase, err := scanPhase(r.db.QueryRow(ctx, query, phaseId, projectId, ownerId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrPhaseNotFound
+		}
+		return nil, fmt.Errorf("failed to get phase by id: %w", err)
+	}
+	return phase, nil
+}
+
+func (r *ProjectManagerRepo) UpdatePhase(ctx context.Context, phase *Phase) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Locking the phase blocks tasks from being linked to it until the update commits
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT phase_id FROM phases WHERE phase_id = $1 AND project_id = $2 AND owner_id = $3 FOR UPDATE`, phase.PhaseId, phase.ProjectId, ownerId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrPhaseNotFound
+		}
+		return fmt.Errorf("failed to lock phase: %w", err)
+	}
+
+	// A phase can only be finished once all of its tasks are done, the tasks
+	// stay locked so none of them can be reopened in the meantime
+	if phase.Status == StatusFinished {
+		var openTasks int
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FILTER (WHERE NOT completed) FROM (SELECT completed FROM tasks WHERE phase_id = $1 FOR SHARE) t`, phase.PhaseId).Scan(&openTasks)
+		if err != nil {
+			return fmt.Errorf("failed to count open phase tasks: %w", err)
+		}
+		if openTasks > 0 {
+			return errorutils.ErrPhaseHasOpenTasks
+		}
+	}
+
+	query := `UPDATE phases SET title=$1, description=$2, status=$3, updated_at=NOW() WHERE phase_id=$4 RETURNING updated_at`
+	err = tx.QueryRow(ctx, query,
+		phase.Title,
+		phase.Description,
+		phase.Status,
+		phase.PhaseId,
+	).Scan(&phase.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update phase: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) DeletePhase(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockProject(ctx, tx, projectId)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM phases WHERE phase_id = $1 AND project_id = $2`, phaseId, projectId)
+	if err != nil {
+		return fmt.Errorf("failed to delete phase: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrPhaseNotFound
+	}
+
+	// Close the gap the deleted phase left behind
+	query := `UPDATE phases p SET position = ordered.new_position
+		FROM (SELECT phase_id, ROW_NUMBER() OVER (ORDER BY position) - 1 AS new_position FROM phases WHERE project_id = $1) ordered
+		WHERE p.phase_id = ordered.phase_id AND p.position <> ordered.new_position`
+	_, err = tx.Exec(ctx, query, projectId)
+	if err != nil {
+		return fmt.Errorf("failed to compact phase positions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) ReorderPhases(ctx context.Context, projectId uuid.UUID, phaseIds []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = lockProject(ctx, tx, projectId)
+	if err != nil {
+		return err
+	}
+
+	var total int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM phases WHERE project_id = $1`, projectId).Scan(&total)
+	if err != nil {
+		return fmt.Errorf("failed to count phases: %w", err)
+	}
+	if total != len(phaseIds) {
+		return errorutils.ErrInvalidPhaseReorder
+	}
+
+	// Position of every phase is its index in phaseIds, the unique
+	// (project_id, position) constraint is only checked on commit
+	query := `UPDATE phases p SET position = ordered.idx - 1, updated_at = NOW()
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(id, idx)
+		WHERE p.phase_id = ordered.id AND p.project_id = $2`
+	tag, err := tx.Exec(ctx, query, phaseIds, projectId)
+	if err != nil {
+		return fmt.Errorf("failed to reorder phases: %w", err)
+	}
+	if int(tag.RowsAffected()) != total {
+		return errorutils.ErrInvalidPhaseReorder
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProjectManagerRepo) GetPhaseSummaries(ctx context.Context, projectId uuid.UUID) ([]*PhaseSummary, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// One row per phase (even without tasks) plus one row for tasks without phase
+	query := `SELECT phase_id, title, open, completed, overdue, next_deadline, last_activity FROM (
+			SELECT ph.phase_id, ph.title, ph.position, ` + taskRollupColumns + `
+			FROM phases ph
+			LEFT JOIN tasks t ON t.phase_id = ph.phase_id AND t.project_id = ph.project_id AND t.owner_id = $2
+			WHERE ph.project_id = $1 AND ph.owner_id = $2
+			GROUP BY ph.phase_id, ph.title, ph.position
+			UNION ALL
+			SELECT NULL, '', NULL, ` + taskRollupColumns + `
+			FROM tasks t
+			WHERE t.project_id = $1 AND t.phase_id IS NULL AND t.owner_id = $2
+			HAVING COUNT(t.task_id) > 0
+		) rollup
+		ORDER BY position NULLS LAST`
+	rows, err := r.db.Query(ctx, query, projectId, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query phase summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]*PhaseSummary, 0)
+	for rows.Next() {
+		var summary PhaseSummary
+		err := rows.Scan(
+			&summary.PhaseId,
+			&summary.Title,
+			&summary.Open,
+			&summary.Completed,
+			&summary.Overdue,
+			&summary.NextDeadline,
+			&summary.LastActivityAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan phase summary: %w", err)
+		}
+		summaries = append(summaries, &summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return summaries, nil
+}
+
+func (r *ProjectManagerRepo) GetProjectSummaries(ctx context.Context) (map[uuid.UUID]*ProjectSummary, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregate all projects in one pass instead of querying per project
+	query := `SELECT t.project_id, ` + taskRollupColumns + `
+		FROM tasks t
+		WHERE t.project_id IS NOT NULL AND t.owner_id = $1
+		GROUP BY t.project_id`
+	rows, err := r.db.Query(ctx, query, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make(map[uuid.UUID]*ProjectSummary)
+	for rows.Next() {
+		var summary ProjectSummary
+		err := rows.Scan(
+			&summary.ProjectId,
+			&summary.Open,
+			&summary.Completed,
+			&summary.Overdue,
+			&summary.NextDeadline,
+			&summary.LastActivityAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project summary: %w", err)
+		}
+		summaries[summary.ProjectId] = &summary
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return summaries, nil
+}
+
+// lockProject locks the project row inside tx so position changes of its
+// phases are serialized. Projects of other users can't be locked.
+func lockProject(ctx context.Context, tx pgx.Tx, projectId uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT project_id FROM projects WHERE project_id = $1 AND owner_id = $2 FOR UPDATE`, projectId, ownerId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrProjectNotFound
+		}
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	return nil
+}
+
+// lockTechStack locks the catalogue of the owner inside tx so concurrent
+// creates, deletes and reorders can't interleave. Other users' catalogues stay
+// writable, the unique (owner_id, position) constraint catches two first items.
+func lockTechStack(ctx context.Context, tx pgx.Tx, ownerId uuid.UUID) error {
+	_, err := tx.Exec(ctx, `SELECT tech_stack_item_id FROM tech_stack_items WHERE owner_id = $1 FOR UPDATE`, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to lock tech stack items: %w", err)
+	}
+	return nil
+}
+
+// lockProjectAt locks the project like lockProject and fails with
+// ErrProjectModified unless it is still at version
+func lockProjectAt(ctx context.Context, tx pgx.Tx, projectId uuid.UUID, version int) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = tx.QueryRow(ctx, `SELECT version FROM projects WHERE project_id = $1 AND owner_id = $2 FOR UPDATE`, projectId, ownerId).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrProjectNotFound
+		}
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	if current != version {
+		return errorutils.ErrProjectModified
+	}
+	return nil
+}
+
+// insertTechStackLinks associates every tech stack id of the project with it inside tx
+func insertTechStackLinks(ctx context.Context, tx pgx.Tx, project *Project) error {
 	for _, techStackId := range project.TechStackIds {
-		_, err = tx.Exec(ctx, `INSERT INTO project_tech_stack (project_id, tech_stack_item_id) VALUES ($1, $2)`, project.ProjectId, techStackId)
+		_, err := tx.Exec(ctx, `INSERT INTO project_tech_stack (project_id, tech_stack_item_id) VALUES ($1, $2)`, project.ProjectId, techStackId)
 		if err != nil {
+			if isViolation(err, pgForeignKeyViolation) {
+				return errorutils.ErrUnknownTechStackItem
+			}
 			return fmt.Errorf("failed to associate tech stack item with project: %w", err)
 		}
 	}
+	return nil
+}
 
-	return tx.Commit(ctx)
+// insertTagLinks associates every tag id of the project with it inside tx
+func insertTagLinks(ctx context.Context, tx pgx.Tx, project *Project) error {
+	for _, tagId := range project.TagIds {
+		_, err := tx.Exec(ctx, `INSERT INTO project_tags (project_id, tag_id) VALUES ($1, $2)`, project.ProjectId, tagId)
+		if err != nil {
+			if isViolation(err, pgForeignKeyViolation) {
+				return errorutils.ErrUnknownTag
+			}
+			return fmt.Errorf("failed to associate tag with project: %w", err)
+		}
+	}
+	return nil
+}
+
+// scanProject scans a row selected with projectSelect into a Project
+func scanProject(row pgx.Row) (*Project, error) {
+	var project Project
+	err := row.Scan(
+		&project.ProjectId,
+		&project.Title,
+		&project.Description,
+		&project.Status,
+		&project.GithubUrl,
+		&project.LiveUrl,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.Version,
+		&project.TechStackIds,
+		&project.TagIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// scanTechStackItem scans a row selected with techStackSelect into a TechStackItem
+func scanTechStackItem(row pgx.Row) (*TechStackItem, error) {
+	var item TechStackItem
+	err := row.Scan(
+		&item.TechStackItemId,
+		&item.Name,
+		&item.Color,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.UsageCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// isUniqueViolation reports whether err was caused by a violated unique constraint
+func isUniqueViolation(err error) bool {
+	return isViolation(err, pgUniqueViolation)
+}
+
+// isViolation reports whether err was caused by the Postgres error code
+func isViolation(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+// scanPhase scans a row selected with phaseColumns into a Phase
+func scanPhase(row pgx.Row) (*Phase, error) {
+	var phase Phase
+	err := row.Scan(
+		&phase.PhaseId,
+		&phase.ProjectId,
+		&phase.Title,
+		&phase.Description,
+		&phase.Status,
+		&phase.Position,
+		&phase.CreatedAt,
+		&phase.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &phase, nil
 }
@@ -0,0 +1,112 @@
+package projectmanager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/database/dbtest"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+)
+
+func TestProjectManagerRepoIsolatesOwners(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewProjectManagerRepo(db)
+	alice := dbtest.UserContext(t, db)
+	bob := dbtest.UserContext(t, db)
+
+	project := &Project{Title: "Alice's project", Status: StatusOngoing}
+	if err := repo.CreateProject(alice, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	phase := &Phase{ProjectId: project.ProjectId, Title: "Alice's phase", Status: StatusPlanning}
+	if err := repo.CreatePhase(alice, phase); err != nil {
+		t.Fatalf("failed to create phase: %v", err)
+	}
+	item := &TechStackItem{Name: "Go", Color: "#00ADD8"}
+	if err := repo.CreateTechStackItem(alice, item); err != nil {
+		t.Fatalf("failed to create tech stack item: %v", err)
+	}
+
+	// Projects
+	if _, err := repo.GetProjectById(bob, project.ProjectId); !errors.Is(err, errorutils.ErrProjectNotFound) {
+		t.Errorf("GetProjectById = %v, want ErrProjectNotFound", err)
+	}
+
+	changedProject := *project
+	changedProject.Title = "Taken over"
+	if err := repo.UpdateProject(bob, &changedProject); !errors.Is(err, errorutils.ErrProjectNotFound) {
+		t.Errorf("UpdateProject = %v, want ErrProjectNotFound", err)
+	}
+
+	if err := repo.DeleteProject(bob, project.ProjectId, project.Version); !errors.Is(err, errorutils.ErrProjectNotFound) {
+		t.Errorf("DeleteProject = %v, want ErrProjectNotFound", err)
+	}
+
+	projects, err := repo.GetAllProjects(bob, ProjectFilter{})
+	if err != nil {
+		t.Fatalf("GetAllProjects failed: %v", err)
+	}
+	if len(projects) != 0 {
+		t.Errorf("GetAllProjects returned %d projects of another user", len(projects))
+	}
+
+	// Phases
+	foreignPhase := &Phase{ProjectId: project.ProjectId, Title: "Bob's phase", Status: StatusPlanning}
+	if err := repo.CreatePhase(bob, foreignPhase); !errors.Is(err, errorutils.ErrProjectNotFound) {
+		t.Errorf("CreatePhase = %v, want ErrProjectNotFound", err)
+	}
+
+	phases, err := repo.GetPhasesByProject(bob, project.ProjectId)
+	if err != nil {
+		t.Fatalf("GetPhasesByProject failed: %v", err)
+	}
+	if len(phases) != 0 {
+		t.Errorf("GetPhasesByProject returned %d phases of another user", len(phases))
+	}
+
+	if _, err := repo.GetPhaseById(bob, project.ProjectId, phase.PhaseId); !errors.Is(err, errorutils.ErrPhaseNotFound) {
+		t.Errorf("GetPhaseById = %v, want ErrPhaseNotFound", err)
+	}
+
+	changedPhase := *phase
+	changedPhase.Title = "Taken over"
+	if err := repo.UpdatePhase(bob, &changedPhase); !errors.Is(err, errorutils.ErrPhaseNotFound) {
+		t.Errorf("UpdatePhase = %v, want ErrPhaseNotFound", err)
+	}
+
+	if err := repo.DeletePhase(bob, project.ProjectId, phase.PhaseId); !errors.Is(err, errorutils.ErrProjectNotFound) {
+		t.Errorf("DeletePhase = %v, want ErrProjectNotFound", err)
+	}
+
+	// Tech stack
+	if _, err := repo.GetTechStackItemById(bob, item.TechStackItemId); !errors.Is(err, errorutils.ErrTechStackItemNotFound) {
+		t.Errorf("GetTechStackItemById = %v, want ErrTechStackItemNotFound", err)
+	}
+
+	changedItem := *item
+	changedItem.Name = "Taken over"
+	if err := repo.UpdateTechStackItem(bob, &changedItem); !errors.Is(err, errorutils.ErrTechStackItemNotFound) {
+		t.Errorf("UpdateTechStackItem = %v, want ErrTechStackItemNotFound", err)
+	}
+
+	if err := repo.DeleteTechStackItem(bob, item.TechStackItemId); !errors.Is(err, errorutils.ErrTechStackItemNotFound) {
+		t.Errorf("DeleteTechStackItem = %v, want ErrTechStackItemNotFound", err)
+	}
+
+	items, err := repo.GetAllTechStackItems(bob)
+	if err != nil {
+		t.Fatalf("GetAllTechStackItems failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("GetAllTechStackItems returned %d items of another user", len(items))
+	}
+
+	// Alice's project is untouched
+	current, err := repo.GetProjectById(alice, project.ProjectId)
+	if err != nil {
+		t.Fatalf("GetProjectById of the owner failed: %v", err)
+	}
+	if current.Title != project.Title || current.Version != project.Version {
+		t.Errorf("project was changed by another user: %+v", current)
+	}
+}
@@ -2,11 +2,19 @@ package projectmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
 	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/J0kerul/jokers-hub/pkg/validation"
+	"github.com/google/uuid"
 )
 
+// hexColorPattern matches short and long hex colors such as #0af or #00aaff
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
 type ProjectManagerService struct {
 	repo ProjectManagerRepositoryInterface
 }
@@ -16,8 +24,9 @@ func NewProjectManagerService(repo ProjectManagerRepositoryInterface) *ProjectMa
 }
 
 func (s *ProjectManagerService) CreateProjectSrc(ctx context.Context, project *Project) error {
-	// Validate project fields
-	if err := checkFields(*project); err != nil {
+	// Validate project fields and references
+	project.TagIds = uniqueIds(project.TagIds)
+	if err := s.checkFields(ctx, *project); err != nil {
 		return err
 	}
 
@@ -29,26 +38,432 @@ func (s *ProjectManagerService) CreateProjectSrc(ctx context.Context, project *P
 	return nil
 }
 
-func checkFields(project Project) error {
-	if project.Title == "" {
-		return errorutils.ErrTitleRequired
+func (s *ProjectManagerService) GetAllProjectsSrc(ctx context.Context, filter ProjectFilter) ([]*Project, error) {
+	filter.TagIds = uniqueIds(filter.TagIds)
+	projects, err := s.repo.GetAllProjects(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *ProjectManagerService) GetProjectByIdSrc(ctx context.Context, projectId uuid.UUID) (*Project, error) {
+	// Check if id isn't empty
+	if projectId == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	project, err := s.repo.GetProjectById(ctx, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project by id: %w", err)
 	}
+	return project, nil
+}
 
-	if project.Description == "" {
-		return errorutils.ErrMissingDescription
+func (s *ProjectManagerService) UpdateProjectSrc(ctx context.Context, project *Project) error {
+	// Check if id isn't empty
+	if project.ProjectId == uuid.Nil {
+		return errorutils.ErrMissingId
 	}
 
-	if !isValidStatus(project.Status) {
-		return errorutils.ErrInvalidStatus
+	// Validate project fields and references
+	project.TagIds = uniqueIds(project.TagIds)
+	if err := s.checkFields(ctx, *project); err != nil {
+		return err
 	}
 
-	if len(project.TechStackIds) == 0 {
-		return errorutils.ErrNoTechStackItems
+	// Update project and its tech stack in the repository
+	err := s.repo.UpdateProject(ctx, project)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	return nil
+}
+
+// DeleteProjectSrc deletes the project if it is still at version
+func (s *ProjectManagerService) DeleteProjectSrc(ctx context.Context, projectId uuid.UUID, version int) error {
+	// Check if id isn't empty
+	if projectId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.DeleteProject(ctx, projectId, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) CreateTechStackItemSrc(ctx context.Context, item *TechStackItem) error {
+	// Validate tech stack fields
+	if err := checkTechStackFields(*item); err != nil {
+		return err
 	}
 
+	err := s.repo.CreateTechStackItem(ctx, item)
+	if err != nil {
+		return fmt.Errorf("failed to create tech stack item: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) GetAllTechStackItemsSrc(ctx context.Context) ([]*TechStackItem, error) {
+	items, err := s.repo.GetAllTechStackItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all tech stack items: %w", err)
+	}
+	return items, nil
+}
+
+func (s *ProjectManagerService) GetTechStackItemByIdSrc(ctx context.Context, itemId uuid.UUID) (*TechStackItem, error) {
+	// Check if id isn't empty
+	if itemId == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	item, err := s.repo.GetTechStackItemById(ctx, itemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tech stack item by id: %w", err)
+	}
+	return item, nil
+}
+
+func (s *ProjectManagerService) UpdateTechStackItemSrc(ctx context.Context, item *TechStackItem) error {
+	// Check if id isn't empty
+	if item.TechStackItemId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Validate tech stack fields
+	if err := checkTechStackFields(*item); err != nil {
+		return err
+	}
+
+	err := s.repo.UpdateTechStackItem(ctx, item)
+	if err != nil {
+		return fmt.Errorf("failed to update tech stack item: %w", err)
+	}
 	return nil
 }
 
+func (s *ProjectManagerService) DeleteTechStackItemSrc(ctx context.Context, itemId uuid.UUID) error {
+	// Check if id isn't empty
+	if itemId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.DeleteTechStackItem(ctx, itemId)
+	if err != nil {
+		return fmt.Errorf("failed to delete tech stack item: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) ReorderTechStackItemsSrc(ctx context.Context, itemIds []uuid.UUID) error {
+	// Every item may only appear once in the new order
+	seen := make(map[uuid.UUID]bool, len(itemIds))
+	for _, id := range itemIds {
+		if id == uuid.Nil || seen[id] {
+			return errorutils.ErrInvalidReorder
+		}
+		seen[id] = true
+	}
+
+	err := s.repo.ReorderTechStackItems(ctx, itemIds)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidReorder) {
+			return err
+		}
+		return fmt.Errorf("failed to reorder tech stack items: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) CreatePhaseSrc(ctx context.Context, phase *Phase) error {
+	// Check if project id isn't empty
+	if phase.ProjectId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Validate phase fields
+	if err := checkPhaseFields(*phase); err != nil {
+		return err
+	}
+
+	// A new phase has no tasks yet, so it may start in any status
+	err := s.repo.CreatePhase(ctx, phase)
+	if err != nil {
+		return fmt.Errorf("failed to create phase: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) GetPhasesByProjectSrc(ctx context.Context, projectId uuid.UUID) ([]*Phase, error) {
+	// Check if id isn't empty
+	if projectId == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	phases, err := s.repo.GetPhasesByProject(ctx, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phases of project: %w", err)
+	}
+	return phases, nil
+}
+
+func (s *ProjectManagerService) GetPhaseByIdSrc(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) (*Phase, error) {
+	// Check if ids aren't empty
+	if projectId == uuid.Nil || phaseId == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	phase, err := s.repo.GetPhaseById(ctx, projectId, phaseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phase by id: %w", err)
+	}
+	return phase, nil
+}
+
+func (s *ProjectManagerService) UpdatePhaseSrc(ctx context.Context, phase *Phase) error {
+	// Check if ids aren't empty
+	if phase.ProjectId == uuid.Nil || phase.PhaseId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Validate phase fields
+	if err := checkPhaseFields(*phase); err != nil {
+		return err
+	}
+
+	// The repository refuses to finish a phase with open tasks
+	err := s.repo.UpdatePhase(ctx, phase)
+	if err != nil {
+		return fmt.Errorf("failed to update phase: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) DeletePhaseSrc(ctx context.Context, projectId uuid.UUID, phaseId uuid.UUID) error {
+	// Check if ids aren't empty
+	if projectId == uuid.Nil || phaseId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.DeletePhase(ctx, projectId, phaseId)
+	if err != nil {
+		return fmt.Errorf("failed to delete phase: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) ReorderPhasesSrc(ctx context.Context, projectId uuid.UUID, phaseIds []uuid.UUID) error {
+	// Check if id isn't empty
+	if projectId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Every phase may only appear once in the new order
+	seen := make(map[uuid.UUID]bool, len(phaseIds))
+	for _, id := range phaseIds {
+		if id == uuid.Nil || seen[id] {
+			return errorutils.ErrInvalidPhaseReorder
+		}
+		seen[id] = true
+	}
+
+	err := s.repo.ReorderPhases(ctx, projectId, phaseIds)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidPhaseReorder) {
+			return err
+		}
+		return fmt.Errorf("failed to reorder phases: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectManagerService) GetProjectSummarySrc(ctx context.Context, project *Project) (*ProjectSummary, error) {
+	phases, err := s.repo.GetPhaseSummaries(ctx, project.ProjectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project summary: %w", err)
+	}
+
+	// Roll the phases up into the project totals
+	summary := &ProjectSummary{ProjectId: project.ProjectId, Phases: phases}
+	for _, phase := range phases {
+		summary.Open += phase.Open
+		summary.Completed += phase.Completed
+		summary.Overdue += phase.Overdue
+		summary.NextDeadline = earliest(summary.NextDeadline, phase.NextDeadline)
+		summary.LastActivityAt = latest(summary.LastActivityAt, phase.LastActivityAt)
+	}
+
+	deriveSummaryFields(summary, project.Status, time.Now())
+	return summary, nil
+}
+
+func (s *ProjectManagerService) GetProjectSummariesSrc(ctx context.Context, projects []*Project) (map[uuid.UUID]*ProjectSummary, error) {
+	summaries, err := s.repo.GetProjectSummaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project summaries: %w", err)
+	}
+
+	now := time.Now()
+	for _, project := range projects {
+		// Projects without tasks don't show up in the aggregate
+		summary, ok := summaries[project.ProjectId]
+		if !ok {
+			summary = &ProjectSummary{ProjectId: project.ProjectId}
+			summaries[project.ProjectId] = summary
+		}
+		deriveSummaryFields(summary, project.Status, now)
+	}
+
+	return summaries, nil
+}
+
+// checkTechStackIds returns a validation error if one of the ids has no tech stack item
+func (s *ProjectManagerService) checkTechStackIds(ctx context.Context, itemIds []uuid.UUID) error {
+	unknown, err := s.repo.FindUnknownTechStackIds(ctx, itemIds)
+	if err != nil {
+		return fmt.Errorf("failed to check tech stack items: %w", err)
+	}
+
+	if len(unknown) > 0 {
+		return errorutils.ErrUnknownTechStackItem
+	}
+	return nil
+}
+
+// checkTagIds returns a validation error if one of the ids has no tag
+func (s *ProjectManagerService) checkTagIds(ctx context.Context, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	unknown, err := s.repo.FindUnknownTagIds(ctx, tagIds)
+	if err != nil {
+		return fmt.Errorf("failed to check tags: %w", err)
+	}
+
+	if len(unknown) > 0 {
+		return errorutils.ErrUnknownTag
+	}
+	return nil
+}
+
+// uniqueIds drops repeated ids while keeping the order of first appearance
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// deriveSummaryFields computes percent complete, days since last activity and the stalled flag
+func deriveSummaryFields(summary *ProjectSummary, status Status, now time.Time) {
+	total := summary.Open + summary.Completed
+	if total > 0 {
+		summary.PercentComplete = float64(summary.Completed) * 100 / float64(total)
+	}
+
+	if summary.LastActivityAt != nil {
+		days := int(now.Sub(*summary.LastActivityAt).Hours() / 24)
+		summary.DaysSinceLastActivity = &days
+	}
+
+	// Only projects that are being worked on can stall
+	summary.Stalled = isActiveStatus(status) &&
+		summary.Open > 0 &&
+		summary.DaysSinceLastActivity != nil &&
+		*summary.DaysSinceLastActivity >= StalledAfterDays
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// checkFields validates the fields and references of project and returns every
+// violation together
+func (s *ProjectManagerService) checkFields(ctx context.Context, project Project) error {
+	v := validation.New()
+
+	// Check title and description
+	v.Required(project.Title, errorutils.ErrTitleRequired)
+	v.MaxLength("title", project.Title, MaxTitleLength)
+	v.Required(project.Description, errorutils.ErrMissingDescription)
+	v.MaxLength("description", project.Description, MaxDescriptionLength)
+
+	v.Check(isValidStatus(project.Status), errorutils.ErrInvalidStatus)
+
+	// Links are optional but have to point somewhere
+	if project.GithubUrl != nil {
+		v.URL("github_url", *project.GithubUrl)
+		v.MaxLength("github_url", *project.GithubUrl, MaxURLLength)
+	}
+	if project.LiveUrl != nil {
+		v.URL("live_url", *project.LiveUrl)
+		v.MaxLength("live_url", *project.LiveUrl, MaxURLLength)
+	}
+
+	// Make sure every referenced tech stack item and tag exists
+	v.Check(len(project.TechStackIds) > 0, errorutils.ErrNoTechStackItems)
+	if len(project.TechStackIds) > 0 {
+		if err := v.Collect(s.checkTechStackIds(ctx, project.TechStackIds)); err != nil {
+			return err
+		}
+	}
+	if err := v.Collect(s.checkTagIds(ctx, project.TagIds)); err != nil {
+		return err
+	}
+
+	return v.Err()
+}
+
+func checkTechStackFields(item TechStackItem) error {
+	v := validation.New()
+	v.Required(item.Name, errorutils.ErrNameRequired)
+	v.MaxLength("name", item.Name, MaxNameLength)
+	v.Check(hexColorPattern.MatchString(item.Color), errorutils.ErrInvalidColor)
+	return v.Err()
+}
+
+func checkPhaseFields(phase Phase) error {
+	v := validation.New()
+	v.Required(phase.Title, errorutils.ErrTitleRequired)
+	v.MaxLength("title", phase.Title, MaxTitleLength)
+	v.MaxLength("description", phase.Description, MaxDescriptionLength)
+	v.Check(isValidStatus(phase.Status), errorutils.ErrInvalidStatus)
+	return v.Err()
+}
+
+func isActiveStatus(status Status) bool {
+	switch status {
+	case StatusPlanning, StatusOngoing, StatusTesting, StatusBugFixes, StatusRefactoring:
+		return true
+	default:
+		return false
+	}
+}
+
 func isValidStatus(status Status) bool {
 	switch status {
 	case StatusIdea, StatusPlanning, StatusOngoing, StatusTesting,
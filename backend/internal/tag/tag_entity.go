@@ -0,0 +1,17 @@
+package tag
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	TagId        uuid.UUID `json:"tag_id" db:"tag_id"`
+	Name         string    `json:"name" db:"name"`
+	Color        string    `json:"color" db:"color"`
+	TaskCount    int       `json:"task_count" db:"-"`
+	ProjectCount int       `json:"project_count" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
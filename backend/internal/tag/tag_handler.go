@@ -0,0 +1,246 @@
+package tag
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/J0kerul/jokers-hub/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CreateTagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+type MergeTagRequest struct {
+	IntoTagId uuid.UUID `json:"into_tag_id"`
+}
+
+type TagResponse struct {
+	TagId        uuid.UUID `json:"tag_id"`
+	Name         string    `json:"name"`
+	Color        string    `json:"color"`
+	TaskCount    int       `json:"task_count"`
+	ProjectCount int       `json:"project_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type TagHandler struct {
+	service TagServiceInterface
+}
+
+func NewTagHandler(service TagServiceInterface) *TagHandler {
+	return &TagHandler{
+		service: service,
+	}
+}
+
+// createTag handles POST /tags
+func (h *TagHandler) createTag(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Request Body
+	var req CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 2. DTO → Entity
+	tag := &Tag{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+
+	// 3. Call Service Layer
+	err := h.service.CreateTag(r.Context(), tag)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to create tag")
+		return
+	}
+
+	// 4. Send Response
+	utils.RespondWithJSON(w, http.StatusCreated, tagToResponse(tag))
+}
+
+// getAllTags handles GET /tags
+func (h *TagHandler) getAllTags(w http.ResponseWriter, r *http.Request) {
+	// 1. Call Service Layer
+	tags, err := h.service.GetAllTags(r.Context())
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve tags")
+		return
+	}
+
+	// 2. Entity → Response DTOs
+	response := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = tagToResponse(tag)
+	}
+
+	// 3. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// getTagById handles GET /tags/:id
+func (h *TagHandler) getTagById(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tag ID")
+		return
+	}
+
+	// 2. Call Service Layer
+	tag, err := h.service.GetTagById(r.Context(), tagID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get tag")
+		return
+	}
+
+	// 3. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, tagToResponse(tag))
+}
+
+// updateTag handles PUT /tags/:id, renaming or recoloring the tag
+func (h *TagHandler) updateTag(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tag ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Existing Tag
+	tag, err := h.service.GetTagById(r.Context(), tagID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get tag")
+		return
+	}
+
+	// 4. Update Fields
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	// 5. Call Service Layer
+	err = h.service.UpdateTag(r.Context(), tag)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to update tag")
+		return
+	}
+
+	// 6. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, tagToResponse(tag))
+}
+
+// deleteTag handles DELETE /tags/:id
+func (h *TagHandler) deleteTag(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tag ID")
+		return
+	}
+
+	// 2. Call Service Layer to Delete Tag
+	err = h.service.DeleteTag(r.Context(), tagID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to delete tag")
+		return
+	}
+
+	// 3. Send No Content Response
+	utils.RespondWithNoContent(w)
+}
+
+// mergeTag handles POST /tags/:id/merge, folding the tag into into_tag_id
+func (h *TagHandler) mergeTag(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid tag ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req MergeTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Make Sure Both Tags Exist
+	if _, err := h.service.GetTagById(r.Context(), tagID); err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get tag")
+		return
+	}
+	if _, err := h.service.GetTagById(r.Context(), req.IntoTagId); err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get target tag")
+		return
+	}
+
+	// 4. Call Service Layer
+	err = h.service.MergeTags(r.Context(), tagID, req.IntoTagId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to merge tags")
+		return
+	}
+
+	// 5. Respond with the Surviving Tag
+	tag, err := h.service.GetTagById(r.Context(), req.IntoTagId)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve merged tag")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, tagToResponse(tag))
+}
+
+// tagToResponse converts Tag entity to response DTO
+func tagToResponse(tag *Tag) TagResponse {
+	return TagResponse{
+		TagId:        tag.TagId,
+		Name:         tag.Name,
+		Color:        tag.Color,
+		TaskCount:    tag.TaskCount,
+		ProjectCount: tag.ProjectCount,
+		CreatedAt:    tag.CreatedAt,
+		UpdatedAt:    tag.UpdatedAt,
+	}
+}
+
+// RegisterRoutes registers all tag-related routes
+func RegisterRoutes(r chi.Router, handler *TagHandler) {
+	r.Route("/tags", func(r chi.Router) {
+		// Create
+		r.Post("/", handler.createTag) // POST /tags
+
+		// Read
+		r.Get("/", handler.getAllTags)     // GET /tags
+		r.Get("/{id}", handler.getTagById) // GET /tags/:id
+
+		// Update
+		r.Put("/{id}", handler.updateTag)       // PUT /tags/:id
+		r.Post("/{id}/merge", handler.mergeTag) // POST /tags/:id/merge
+
+		// Delete
+		r.Delete("/{id}", handler.deleteTag) // DELETE /tags/:id
+	})
+}
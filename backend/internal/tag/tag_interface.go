@@ -0,0 +1,25 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type TagRepositoryInterface interface {
+	Create(ctx context.Context, tag *Tag) error
+	GetAll(ctx context.Context) ([]*Tag, error)
+	GetById(ctx context.Context, tagid uuid.UUID) (*Tag, error)
+	Update(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, tagid uuid.UUID) error
+	Merge(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error
+}
+
+type TagServiceInterface interface {
+	CreateTag(ctx context.Context, tag *Tag) error
+	GetAllTags(ctx context.Context) ([]*Tag, error)
+	GetTagById(ctx context.Context, tagid uuid.UUID) (*Tag, error)
+	UpdateTag(ctx context.Context, tag *Tag) error
+	DeleteTag(ctx context.Context, tagid uuid.UUID) error
+	MergeTags(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error
+}
@@ -0,0 +1,214 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUniqueViolation is the Postgres error code for a violated unique constraint
+const pgUniqueViolation = "23505"
+
+// tagSelect selects every tag of the user passed as $1 together with the
+// number of linked tasks and projects
+const tagSelect = `SELECT g.tag_id, g.name, g.color, g.created_at, g.updated_at,
+	(SELECT COUNT(*) FROM task_tags tt JOIN tasks t ON t.task_id = tt.task_id WHERE tt.tag_id = g.tag_id AND t.owner_id = $1) AS task_count,
+	(SELECT COUNT(*) FROM project_tags pt JOIN projects p ON p.project_id = pt.project_id WHERE pt.tag_id = g.tag_id AND p.owner_id = $1) AS project_count
+	FROM tags g WHERE g.owner_id = $1`
+
+type TagRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTagRepo(db *pgxpool.Pool) *TagRepo {
+	return &TagRepo{db: db}
+}
+
+func (r *TagRepo) Create(ctx context.Context, tag *Tag) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO tags (name, color, owner_id) VALUES ($1, $2, $3) RETURNING tag_id, created_at, updated_at`
+	err = r.db.QueryRow(ctx, query,
+		tag.Name,
+		tag.Color,
+		ownerId,
+	).Scan(&tag.TagId, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errorutils.ErrTagNameTaken
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepo) GetAll(ctx context.Context) ([]*Tag, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := tagSelect + ` ORDER BY LOWER(g.name)`
+	rows, err := r.db.Query(ctx, query, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]*Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *TagRepo) GetById(ctx context.Context, tagid uuid.UUID) (*Tag, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := tagSelect + ` AND g.tag_id = $2`
+	tag, err := scanTag(r.db.QueryRow(ctx, query, ownerId, tagid))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag by id: %w", err)
+	}
+	return tag, nil
+}
+
+func (r *TagRepo) Update(ctx context.Context, tag *Tag) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Links reference the tag by id, so a rename is visible everywhere at once
+	query := `UPDATE tags SET name=$1, color=$2, updated_at=NOW() WHERE tag_id=$3 AND owner_id=$4 RETURNING updated_at`
+	err = r.db.QueryRow(ctx, query,
+		tag.Name,
+		tag.Color,
+		tag.TagId,
+		ownerId,
+	).Scan(&tag.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrTagNotFound
+		}
+		if isUniqueViolation(err) {
+			return errorutils.ErrTagNameTaken
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepo) Delete(ctx context.Context, tagid uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM tags WHERE tag_id = $1 AND owner_id = $2`
+	result, err := r.db.Exec(ctx, query, tagid, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errorutils.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (r *TagRepo) Merge(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Both tags have to belong to the user before anything is relinked, the
+	// links of the source then only point to the user's tasks and projects
+	result, err := tx.Exec(ctx, `UPDATE tags SET updated_at = NOW() WHERE tag_id = ANY($1) AND owner_id = $2`, []uuid.UUID{sourceid, targetid}, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to lock merged tags: %w", err)
+	}
+	if result.RowsAffected() != 2 {
+		return errorutils.ErrTagNotFound
+	}
+
+	// Relink everything tagged with the source, skipping rows that already carry the target
+	_, err = tx.Exec(ctx, `INSERT INTO task_tags (task_id, tag_id) SELECT task_id, $2 FROM task_tags WHERE tag_id = $1 ON CONFLICT DO NOTHING`, sourceid, targetid)
+	if err != nil {
+		return fmt.Errorf("failed to relink tasks: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO project_tags (project_id, tag_id) SELECT project_id, $2 FROM project_tags WHERE tag_id = $1 ON CONFLICT DO NOTHING`, sourceid, targetid)
+	if err != nil {
+		return fmt.Errorf("failed to relink projects: %w", err)
+	}
+
+	// Deleting the source cascades to its remaining links
+	result, err = tx.Exec(ctx, `DELETE FROM tags WHERE tag_id = $1`, sourceid)
+	if err != nil {
+		return fmt.Errorf("failed to delete merged tag: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errorutils.ErrTagNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// scanTag scans a row selected with tagSelect into a Tag
+func scanTag(row pgx.Row) (*Tag, error) {
+	var tag Tag
+	err := row.Scan(
+		&tag.TagId,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+		&tag.TaskCount,
+		&tag.ProjectCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// isUniqueViolation reports whether err was caused by a violated unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,62 @@
+package tag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/database/dbtest"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+)
+
+func TestTagRepoIsolatesOwners(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewTagRepo(db)
+	alice := dbtest.UserContext(t, db)
+	bob := dbtest.UserContext(t, db)
+
+	tag := &Tag{Name: "Errand", Color: "#F59E0B"}
+	if err := repo.Create(alice, tag); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	// Names only have to be unique per user
+	own := &Tag{Name: "errand", Color: "#3B82F6"}
+	if err := repo.Create(bob, own); err != nil {
+		t.Fatalf("failed to create tag with the name of another user's tag: %v", err)
+	}
+
+	if _, err := repo.GetById(bob, tag.TagId); !errors.Is(err, errorutils.ErrTagNotFound) {
+		t.Errorf("GetById = %v, want ErrTagNotFound", err)
+	}
+
+	changed := *tag
+	changed.Name = "Taken over"
+	if err := repo.Update(bob, &changed); !errors.Is(err, errorutils.ErrTagNotFound) {
+		t.Errorf("Update = %v, want ErrTagNotFound", err)
+	}
+
+	if err := repo.Merge(bob, tag.TagId, own.TagId); !errors.Is(err, errorutils.ErrTagNotFound) {
+		t.Errorf("Merge = %v, want ErrTagNotFound", err)
+	}
+
+	if err := repo.Delete(bob, tag.TagId); !errors.Is(err, errorutils.ErrTagNotFound) {
+		t.Errorf("Delete = %v, want ErrTagNotFound", err)
+	}
+
+	tags, err := repo.GetAll(bob)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(tags) != 1 || tags[0].TagId != own.TagId {
+		t.Errorf("GetAll returned %+v, want only the user's own tag", tags)
+	}
+
+	// Alice's tag is untouched
+	current, err := repo.GetById(alice, tag.TagId)
+	if err != nil {
+		t.Fatalf("GetById of the owner failed: %v", err)
+	}
+	if current.Name != tag.Name {
+		t.Errorf("tag was changed by another user: %+v", current)
+	}
+}
@@ -0,0 +1,128 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// hexColorPattern matches short and long hex colors such as #0af or #00aaff
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+type TagService struct {
+	repo TagRepositoryInterface
+}
+
+func NewTagService(repo TagRepositoryInterface) *TagService {
+	return &TagService{repo: repo}
+}
+
+func (s *TagService) CreateTag(ctx context.Context, tag *Tag) error {
+	// Check for required fields
+	err := checkFields(tag)
+	if err != nil {
+		return err
+	}
+
+	// Create Tag
+	err = s.repo.Create(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TagService) GetAllTags(ctx context.Context) ([]*Tag, error) {
+	tags, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (s *TagService) GetTagById(ctx context.Context, tagid uuid.UUID) (*Tag, error) {
+	// Check if id isn't empty
+	if tagid == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	tag, err := s.repo.GetById(ctx, tagid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag by id: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (s *TagService) UpdateTag(ctx context.Context, tag *Tag) error {
+	// Check if id isn't empty
+	if tag.TagId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Check for required fields
+	err := checkFields(tag)
+	if err != nil {
+		return err
+	}
+
+	// Update Tag
+	err = s.repo.Update(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TagService) DeleteTag(ctx context.Context, tagid uuid.UUID) error {
+	// Check if id isn't empty
+	if tagid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.Delete(ctx, tagid)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+// MergeTags moves every link of the source tag to the target and deletes the source
+func (s *TagService) MergeTags(ctx context.Context, sourceid uuid.UUID, targetid uuid.UUID) error {
+	// Check if ids aren't empty
+	if sourceid == uuid.Nil || targetid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+	if sourceid == targetid {
+		return errorutils.ErrTagMergeIntoSelf
+	}
+
+	err := s.repo.Merge(ctx, sourceid, targetid)
+	if err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	return nil
+}
+
+// checkFields trims the name and validates name and color
+func checkFields(tag *Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return errorutils.ErrNameRequired
+	}
+
+	if !hexColorPattern.MatchString(tag.Color) {
+		return errorutils.ErrInvalidColor
+	}
+
+	return nil
+}
@@ -14,41 +14,152 @@ const (
 	PriorityLow    Priority = "low"
 )
 
+// Domain is the slug of a row in the domains table
 type Domain string
 
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+)
+
+// CompletionPolicy decides what happens when a task with open checklist items is completed
+type CompletionPolicy string
+
 const (
-	DomainWork           Domain = "work"
-	DomainUniversity     Domain = "university"
-	DomainPersonal       Domain = "personal"
-	DomainCoding         Domain = "coding"
-	DomainHealth         Domain = "health"
-	DomainFinance        Domain = "finance"
-	DomainSocial         Domain = "social"
-	DomainHome           Domain = "home"
-	DomainStudy          Domain = "study"
-	DomainTravel         Domain = "travel"
-	DomainAdministration Domain = "administration"
+	CompletionBlock   CompletionPolicy = "block"
+	CompletionCascade CompletionPolicy = "cascade"
 )
 
+// Completion lists what completing a task changes besides the task itself.
+// The repository applies it in the same transaction as the status change.
+type Completion struct {
+	// CompleteChecklist completes the open checklist items of the task
+	CompleteChecklist bool
+	// NextOccurrence is created unless the series already continues past the task
+	NextOccurrence *Task
+}
+
 type Task struct {
-	TaskId      uuid.UUID  `json:"task_id" db:"task_id"`
-	Title       string     `json:"title" db:"title"`
-	Description *string    `json:"description,omitempty" db:"description"`
-	Priority    Priority   `json:"priority" db:"priority"`
-	Domain      Domain     `json:"domain" db:"domain"`
-	ProjectId   *uuid.UUID `json:"project_id,omitempty" db:"project_id"`
-	PhaseId     *uuid.UUID `json:"phase_id,omitempty" db:"phase_id"`
-	UniModuleId *uuid.UUID `json:"uni_module_id,omitempty" db:"uni_module_id"`
-	Deadline    *time.Time `json:"deadline,omitempty" db:"deadline"`
-	IsBacklog   bool       `json:"is_backlog" db:"is_backlog"`
-	Completed   bool       `json:"completed" db:"completed"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-}
-
-/* type TaskFilter struct {
-	Priority  *Priority
-	Domain    *Domain
-	IsBacklog *bool
-	Completed *bool
-} */
+	TaskId       uuid.UUID       `json:"task_id" db:"task_id"`
+	Title        string          `json:"title" db:"title"`
+	Description  *string         `json:"description,omitempty" db:"description"`
+	Priority     Priority        `json:"priority" db:"priority"`
+	Domain       Domain          `json:"domain" db:"domain"`
+	ProjectId    *uuid.UUID      `json:"project_id,omitempty" db:"project_id"`
+	PhaseId      *uuid.UUID      `json:"phase_id,omitempty" db:"phase_id"`
+	UniModuleId  *uuid.UUID      `json:"uni_module_id,omitempty" db:"uni_module_id"`
+	RecurrenceId *uuid.UUID      `json:"recurrence_id,omitempty" db:"recurrence_id"`
+	Recurrence   *RecurrenceRule `json:"recurrence,omitempty" db:"-"`
+	Deadline     *time.Time      `json:"deadline,omitempty" db:"deadline"`
+	IsBacklog    bool            `json:"is_backlog" db:"is_backlog"`
+	Completed    bool            `json:"completed" db:"completed"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+	Version      int             `json:"version" db:"version"`
+
+	ChecklistTotal     int              `json:"checklist_total" db:"checklist_total"`
+	ChecklistCompleted int              `json:"checklist_completed" db:"checklist_completed"`
+	Checklist          []*ChecklistItem `json:"checklist,omitempty" db:"-"`
+
+	// Blocked is set while at least one task in BlockedBy is still open
+	Blocked   bool        `json:"blocked" db:"blocked"`
+	BlockedBy []uuid.UUID `json:"blocked_by,omitempty" db:"-"`
+
+	TagIds []uuid.UUID `json:"tag_ids" db:"-"`
+}
+
+// ChecklistItem is one ordered step of a task
+type ChecklistItem struct {
+	ItemId    uuid.UUID `json:"item_id" db:"item_id"`
+	TaskId    uuid.UUID `json:"task_id" db:"task_id"`
+	Title     string    `json:"title" db:"title"`
+	Completed bool      `json:"completed" db:"completed"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RecurrenceRule describes how a task repeats. Every occurrence is its own
+// task linked to the rule, StartsAt is the deadline of the first occurrence
+// and anchors the weekly and monthly patterns.
+type RecurrenceRule struct {
+	RecurrenceId uuid.UUID  `json:"recurrence_id" db:"recurrence_id"`
+	Frequency    Frequency  `json:"frequency" db:"frequency"`
+	Interval     int        `json:"interval" db:"repeat_interval"`
+	ByWeekday    []int      `json:"by_weekday" db:"by_weekday"` // 0 = Sunday … 6 = Saturday
+	StartsAt     time.Time  `json:"starts_at" db:"starts_at"`
+	Until        *time.Time `json:"until,omitempty" db:"ends_at"`
+	Count        *int       `json:"count,omitempty" db:"max_occurrences"`
+	Occurrences  int        `json:"occurrences" db:"occurrences"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultPageSize is the page size of list requests without a limit,
+// MaxPageSize the largest page a single list request may ask for
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// MaxPreviewOccurrences is the largest number of upcoming occurrences a preview returns
+const MaxPreviewOccurrences = 50
+
+// Maximum lengths of the text fields, in characters
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 5000
+)
+
+type SortField string
+
+const (
+	SortByDeadline  SortField = "deadline"
+	SortByPriority  SortField = "priority"
+	SortByTitle     SortField = "title"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+)
+
+// TaskFilter describes which tasks to list and in which order.
+// Nil fields are not filtered on, a Limit of 0 returns a page of DefaultPageSize.
+type TaskFilter struct {
+	Priority     *Priority
+	Domain       *Domain
+	IsBacklog    *bool
+	Completed    *bool
+	DeadlineFrom *time.Time
+	DeadlineTo   *time.Time
+	ProjectId    *uuid.UUID
+	PhaseId      *uuid.UUID
+	Blocked      *bool
+	TagIds       []uuid.UUID // tasks have to carry every listed tag
+	SortBy       SortField
+	SortDesc     bool
+	Limit        int
+	Cursor       *TaskCursor
+}
+
+// TaskCursor points at the last task of a page. Value holds the sort key
+// of that task, TaskId breaks ties between equal sort keys.
+type TaskCursor struct {
+	SortBy SortField `json:"s"`
+	Value  string    `json:"v"`
+	TaskId uuid.UUID `json:"id"`
+}
+
+type TaskPage struct {
+	Tasks      []*Task
+	NextCursor *TaskCursor
+}
+
+// TaskStats counts open tasks across every user, for the metrics endpoint
+type TaskStats struct {
+	Open    int
+	Overdue int
+	Backlog int
+}
@@ -1,9 +1,13 @@
 package task
 
 import (
+	"encoding/base64"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
@@ -13,23 +17,82 @@ import (
 )
 
 type CreateTaskRequest struct {
-	Title       string   `json:"title"`
-	Description *string  `json:"description,omitempty"`
-	Priority    Priority `json:"priority"`
-	Domain      Domain   `json:"domain"`
-	Deadline    *string  `json:"deadline,omitempty"`
-	IsBacklog   bool     `json:"is_backlog"`
-	Completed   bool     `json:"completed"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Priority    Priority   `json:"priority"`
+	Domain      Domain     `json:"domain"`
+	ProjectId   *uuid.UUID `json:"project_id,omitempty"`
+	PhaseId     *uuid.UUID `json:"phase_id,omitempty"`
+	UniModuleId *uuid.UUID `json:"uni_module_id,omitempty"`
+	Deadline    *string    `json:"deadline,omitempty"`
+	IsBacklog   bool       `json:"is_backlog"`
+	Completed   bool       `json:"completed"`
+
+	Recurrence *RecurrenceRequest `json:"recurrence,omitempty"`
+	TagIds     []uuid.UUID        `json:"tag_ids,omitempty"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string   `json:"title,omitempty"`
-	Description *string   `json:"description,omitempty"`
-	Priority    *Priority `json:"priority,omitempty"`
-	Domain      *Domain   `json:"domain,omitempty"`
-	Deadline    *string   `json:"deadline,omitempty"`
-	IsBacklog   *bool     `json:"is_backlog,omitempty"`
-	Completed   *bool     `json:"completed,omitempty"`
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Priority    *Priority  `json:"priority,omitempty"`
+	Domain      *Domain    `json:"domain,omitempty"`
+	ProjectId   *uuid.UUID `json:"project_id,omitempty"`
+	PhaseId     *uuid.UUID `json:"phase_id,omitempty"`
+	UniModuleId *uuid.UUID `json:"uni_module_id,omitempty"`
+	Deadline    *string    `json:"deadline,omitempty"`
+	IsBacklog   *bool      `json:"is_backlog,omitempty"`
+	Completed   *bool      `json:"completed,omitempty"`
+
+	// TagIds replaces the tags of the task, omitting it keeps them
+	TagIds *[]uuid.UUID `json:"tag_ids,omitempty"`
+}
+
+type RecurrenceRequest struct {
+	Frequency Frequency `json:"frequency"`
+	Interval  int       `json:"interval,omitempty"`
+	ByWeekday []int     `json:"by_weekday,omitempty"`
+	Until     *string   `json:"until,omitempty"`
+	Count     *int      `json:"count,omitempty"`
+}
+
+type RecurrenceResponse struct {
+	RecurrenceId uuid.UUID  `json:"recurrence_id"`
+	Frequency    Frequency  `json:"frequency"`
+	Interval     int        `json:"interval"`
+	ByWeekday    []int      `json:"by_weekday"`
+	StartsAt     time.Time  `json:"starts_at"`
+	Until        *time.Time `json:"until,omitempty"`
+	Count        *int       `json:"count,omitempty"`
+	Occurrences  int        `json:"occurrences"`
+}
+
+type OccurrencePreviewResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+type CreateChecklistItemRequest struct {
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Title     *string `json:"title,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+}
+
+type ReorderChecklistRequest struct {
+	ItemIds []uuid.UUID `json:"item_ids"`
+}
+
+type ChecklistItemResponse struct {
+	ItemId    uuid.UUID `json:"item_id"`
+	TaskId    uuid.UUID `json:"task_id"`
+	Title     string    `json:"title"`
+	Completed bool      `json:"completed"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type TaskResponse struct {
@@ -38,12 +101,34 @@ type TaskResponse struct {
 	Description *string    `json:"description,omitempty"`
 	Priority    Priority   `json:"priority"`
 	Domain      Domain     `json:"domain"`
+	ProjectId   *uuid.UUID `json:"project_id,omitempty"`
+	PhaseId     *uuid.UUID `json:"phase_id,omitempty"`
+	UniModuleId *uuid.UUID `json:"uni_module_id,omitempty"`
 	Deadline    *time.Time `json:"deadline,omitempty"`
 	IsBacklog   bool       `json:"is_backlog"`
 	Completed   bool       `json:"completed"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`
+
+	RecurrenceId *uuid.UUID          `json:"recurrence_id,omitempty"`
+	Recurrence   *RecurrenceResponse `json:"recurrence,omitempty"`
+
+	ChecklistTotal     int                      `json:"checklist_total"`
+	ChecklistCompleted int                      `json:"checklist_completed"`
+	ChecklistRatio     float64                  `json:"checklist_ratio"`
+	Checklist          []*ChecklistItemResponse `json:"checklist,omitempty"`
+
+	Blocked   bool        `json:"blocked"`
+	BlockedBy []uuid.UUID `json:"blocked_by,omitempty"`
+
+	TagIds []uuid.UUID `json:"tag_ids"`
 }
+
+type AddDependencyRequest struct {
+	BlockedByTaskId uuid.UUID `json:"blocked_by_task_id"`
+}
+
 type TaskHandler struct {
 	service TaskServiceInterface
 }
@@ -80,19 +165,27 @@ func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 		Description: req.Description,
 		Priority:    req.Priority,
 		Domain:      req.Domain,
+		ProjectId:   req.ProjectId,
+		PhaseId:     req.PhaseId,
+		UniModuleId: req.UniModuleId,
 		Deadline:    deadline,
 		IsBacklog:   req.IsBacklog,
 		Completed:   false,
+		TagIds:      req.TagIds,
+	}
+	if req.Recurrence != nil {
+		rule, err := recurrenceFromRequest(req.Recurrence)
+		if err != nil {
+			utils.RespondWithProblem(w, r, err, "Invalid recurrence")
+			return
+		}
+		task.Recurrence = rule
 	}
 
 	// 4. Call Service Layer
 	err := h.service.CreateTask(r.Context(), task)
 	if err != nil {
-		if isValidationError(err) {
-			utils.RespondWithBadRequest(w, err.Error())
-			return
-		}
-		utils.RespondWithInternalError(w, fmt.Sprintf("Failed to create task: %v", err))
+		utils.RespondWithProblem(w, r, err, "Failed to create task")
 		return
 	}
 
@@ -100,6 +193,7 @@ func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 	response := taskToResponse(task)
 
 	// 6. Send Response
+	utils.SetETag(w, task.Version)
 	utils.RespondWithJSON(w, http.StatusCreated, response)
 }
 
@@ -120,10 +214,13 @@ func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. Get Existing Task
+	// 3. Get Existing Task and check it is the version the client edited
 	task, err := h.service.GetTaskById(r.Context(), taskID)
 	if err != nil {
-		utils.RespondWithRecordNotFound(w, "task")
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
 		return
 	}
 
@@ -151,6 +248,15 @@ func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 	if req.Domain != nil {
 		task.Domain = *req.Domain
 	}
+	if req.ProjectId != nil {
+		task.ProjectId = req.ProjectId
+	}
+	if req.PhaseId != nil {
+		task.PhaseId = req.PhaseId
+	}
+	if req.UniModuleId != nil {
+		task.UniModuleId = req.UniModuleId
+	}
 	if req.IsBacklog != nil {
 		task.IsBacklog = *req.IsBacklog
 		if *req.IsBacklog {
@@ -163,15 +269,14 @@ func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 	if req.Completed != nil {
 		task.Completed = *req.Completed
 	}
+	if req.TagIds != nil {
+		task.TagIds = *req.TagIds
+	}
 
 	// 6. Call Service Layer to Update
 	err = h.service.UpdateTask(r.Context(), task)
 	if err != nil {
-		if isValidationError(err) {
-			utils.RespondWithBadRequest(w, err.Error())
-			return
-		}
-		utils.RespondWithInternalError(w, "Failed to update task")
+		h.respondWithWriteError(w, r, taskID, err, "Failed to update task")
 		return
 	}
 
@@ -179,9 +284,80 @@ func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 	response := taskToResponse(task)
 
 	// 8. Send Response
+	utils.SetETag(w, task.Version)
 	utils.RespondWithJSON(w, http.StatusOK, response)
 }
 
+// patchTask handles PATCH /tasks/:id with a JSON Merge Patch (RFC 7396) of
+// the fields of UpdateTaskRequest. null removes a field, so description,
+// project_id, phase_id, uni_module_id and deadline can be cleared.
+func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Read Patch
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Existing Task and check it is the version the client edited
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. Merge Patch into the current fields
+	var req UpdateTaskRequest
+	if err := utils.DecodeMergePatch(taskToDocument(task), patch, &req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid merge patch")
+		return
+	}
+
+	var deadline *time.Time
+	if req.Deadline != nil {
+		parsed, err := time.Parse("2006-01-02", *req.Deadline)
+		if err != nil {
+			utils.RespondWithBadRequest(w, "Invalid deadline format")
+			return
+		}
+		deadline = &parsed
+	}
+
+	// 5. Replace every field, the service checks backlog and deadline of the result
+	task.Title = utils.ValueOf(req.Title)
+	task.Description = req.Description
+	task.Priority = utils.ValueOf(req.Priority)
+	task.Domain = utils.ValueOf(req.Domain)
+	task.ProjectId = req.ProjectId
+	task.PhaseId = req.PhaseId
+	task.UniModuleId = req.UniModuleId
+	task.Deadline = deadline
+	task.IsBacklog = utils.ValueOf(req.IsBacklog)
+	task.Completed = utils.ValueOf(req.Completed)
+	task.TagIds = utils.ValueOf(req.TagIds)
+
+	// 6. Call Service Layer to Update
+	err = h.service.UpdateTask(r.Context(), task)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to update task")
+		return
+	}
+
+	// 7. Send Response
+	utils.SetETag(w, task.Version)
+	utils.RespondWithJSON(w, http.StatusOK, taskToResponse(task))
+}
+
 // getTaskById handles GET /tasks/:id
 func (h *TaskHandler) getTaskById(w http.ResponseWriter, r *http.Request) {
 	// 1. Parse ID from URL
@@ -195,7 +371,7 @@ func (h *TaskHandler) getTaskById(w http.ResponseWriter, r *http.Request) {
 	// 2. Call Service Layer to Get Task
 	task, err := h.service.GetTaskById(r.Context(), taskID)
 	if err != nil {
-		utils.RespondWithRecordNotFound(w, "task")
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
 		return
 	}
 
@@ -203,25 +379,103 @@ func (h *TaskHandler) getTaskById(w http.ResponseWriter, r *http.Request) {
 	response := taskToResponse(task)
 
 	// 4. Send Response
+	utils.SetETag(w, task.Version)
 	utils.RespondWithJSON(w, http.StatusOK, response)
 }
 
 // getAllTasks handles GET /tasks
+//
+// Supported query parameters: priority, domain, is_backlog, completed,
+// deadline_from, deadline_to (YYYY-MM-DD), project_id, phase_id,
+// sort (deadline|priority|title|created_at|updated_at), order (asc|desc),
+// limit (default 50, at most 200) and cursor. When more tasks are available
+// the cursor for the next page is returned in the X-Next-Cursor header.
 func (h *TaskHandler) getAllTasks(w http.ResponseWriter, r *http.Request) {
-	// 1. Call Service Layer to Get All Tasks
-	tasks, err := h.service.GetAllTasks(r.Context())
+	// 1. Parse Query Parameters
+	filter, err := parseTaskFilter(r.URL.Query())
 	if err != nil {
-		utils.RespondWithInternalError(w, "Failed to retrieve tasks")
+		utils.RespondWithProblem(w, r, err, "Invalid query parameters")
+		return
+	}
+
+	// 2. List and respond
+	h.listTasks(w, r, filter)
+}
+
+// getProjectTasks handles GET /projects/:id/tasks
+func (h *TaskHandler) getProjectTasks(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+
+	// 2. Parse Query Parameters and scope them to the project
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Invalid query parameters")
+		return
+	}
+	filter.ProjectId = &projectID
+
+	// 3. List and respond
+	h.listTasks(w, r, filter)
+}
+
+// getPhaseTasks handles GET /projects/:id/phases/:phaseId/tasks
+func (h *TaskHandler) getPhaseTasks(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid project ID")
+		return
+	}
+	phaseID, err := uuid.Parse(chi.URLParam(r, "phaseId"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid phase ID")
+		return
+	}
+
+	// 2. Parse Query Parameters and scope them to the phase
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Invalid query parameters")
+		return
+	}
+	filter.ProjectId = &projectID
+	filter.PhaseId = &phaseID
+
+	// 3. List and respond
+	h.listTasks(w, r, filter)
+}
+
+// listTasks lists the tasks matching filter and writes them as response
+func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request, filter TaskFilter) {
+	// 1. Call Service Layer to List Tasks
+	page, err := h.service.ListTasks(r.Context(), filter)
+	if err != nil {
+		// Project and phase filters are part of the path on the nested routes
+		switch {
+		case errors.Is(err, errorutils.ErrUnknownProject):
+			err = errorutils.ErrProjectNotFound
+		case errors.Is(err, errorutils.ErrPhaseNotInProject):
+			err = errorutils.ErrPhaseNotFound
+		}
+		utils.RespondWithProblem(w, r, err, "Failed to retrieve tasks")
 		return
 	}
 
 	// 2. Entity → Response DTOs
-	responses := make([]TaskResponse, len(tasks))
-	for i, task := range tasks {
+	responses := make([]TaskResponse, len(page.Tasks))
+	for i, task := range page.Tasks {
 		responses[i] = taskToResponse(task)
 	}
 
 	// 3. Send Response
+	if page.NextCursor != nil {
+		w.Header().Set("X-Next-Cursor", encodeCursor(page.NextCursor))
+	}
 	utils.RespondWithJSON(w, http.StatusOK, responses)
 }
 
@@ -235,14 +489,24 @@ func (h *TaskHandler) deleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. Call Service Layer to Delete Task
-	err = h.service.DeleteTask(r.Context(), taskID)
+	// 2. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 3. Call Service Layer to Delete Task
+	err = h.service.DeleteTask(r.Context(), taskID, task.Version)
 	if err != nil {
-		utils.RespondWithInternalError(w, "Failed to delete task")
+		h.respondWithWriteError(w, r, taskID, err, "Failed to delete task")
 		return
 	}
 
-	// 3. Send No Content Response
+	// 4. Send No Content Response
 	utils.RespondWithNoContent(w)
 }
 
@@ -256,56 +520,685 @@ func (h *TaskHandler) toggleTaskStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. Call Service Layer to Toggle Status
-	err = h.service.ToggleStatus(r.Context(), taskID)
+	// 2. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
 	if err != nil {
-		utils.RespondWithInternalError(w, "Failed to toggle task status")
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
 		return
 	}
 
-	// 3. Get Updated Task
-	task, err := h.service.GetTaskById(r.Context(), taskID)
+	// 3. Call Service Layer to Toggle Status
+	err = h.service.ToggleStatus(r.Context(), taskID, task.Version)
 	if err != nil {
-		utils.RespondWithInternalError(w, "Failed to fetch updated task")
+		h.respondWithWriteError(w, r, taskID, err, "Failed to toggle task status")
 		return
 	}
 
-	// 4. Entity → Response DTO
+	// 4. Get Updated Task
+	task, err = h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to fetch updated task")
+		return
+	}
+
+	// 5. Entity → Response DTO
 	response := taskToResponse(task)
 
-	// 5. Send Response
+	// 6. Send Response
+	utils.SetETag(w, task.Version)
 	utils.RespondWithJSON(w, http.StatusOK, response)
 }
 
-// isValidationError checks if error is a validation/business logic error
-func isValidationError(err error) bool {
-	switch err {
-	case errorutils.ErrMissingId,
-		errorutils.ErrTitleRequired,
-		errorutils.ErrInvalidPriority,
-		errorutils.ErrInvalidDomain,
-		errorutils.ErrNoDeadlineForNonBacklog,
-		errorutils.ErrBacklogDeadlineConflict:
-		return true
+// setRecurrence handles PUT /tasks/:id/recurrence
+func (h *TaskHandler) setRecurrence(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req RecurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+	rule, err := recurrenceFromRequest(&req)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Invalid recurrence")
+		return
+	}
+
+	// 3. Get Existing Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. Call Service Layer to Set Recurrence
+	err = h.service.SetRecurrence(r.Context(), task, rule)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to set recurrence")
+		return
+	}
+
+	// 5. Send Response
+	utils.SetETag(w, task.Version)
+	utils.RespondWithJSON(w, http.StatusOK, taskToResponse(task))
+}
+
+// deleteRecurrence handles DELETE /tasks/:id/recurrence
+func (h *TaskHandler) deleteRecurrence(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 3. Call Service Layer to Delete Recurrence
+	err = h.service.DeleteRecurrence(r.Context(), task)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to delete recurrence")
+		return
+	}
+
+	// 4. Send No Content Response with the new version of the task
+	utils.SetETag(w, task.Version)
+	utils.RespondWithNoContent(w)
+}
+
+// previewOccurrences handles GET /tasks/:id/recurrence/preview?count=N
+func (h *TaskHandler) previewOccurrences(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Parse Count, defaults to 5
+	count := 5
+	if v := r.URL.Query().Get("count"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil {
+			utils.RespondWithBadRequest(w, errorutils.ErrInvalidPreviewCount.Error())
+			return
+		}
+	}
+
+	// 3. Get Existing Task
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+
+	// 4. Call Service Layer to Compute Occurrences
+	occurrences, err := h.service.PreviewOccurrences(r.Context(), task, count)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to preview occurrences")
+		return
+	}
+
+	// 5. Send Response
+	utils.RespondWithJSON(w, http.StatusOK, OccurrencePreviewResponse{Occurrences: occurrences})
+}
+
+// skipOccurrence handles POST /tasks/:id/recurrence/skip
+func (h *TaskHandler) skipOccurrence(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Get Existing Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 3. Call Service Layer to Skip the Current Occurrence
+	err = h.service.SkipOccurrence(r.Context(), task)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to skip occurrence")
+		return
+	}
+
+	// 4. Send Response
+	utils.SetETag(w, task.Version)
+	utils.RespondWithJSON(w, http.StatusOK, taskToResponse(task))
+}
+
+// createChecklistItem handles POST /tasks/:id/checklist
+func (h *TaskHandler) createChecklistItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req CreateChecklistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. DTO → Entity
+	item := &ChecklistItem{
+		TaskId:    taskID,
+		Title:     req.Title,
+		Completed: req.Completed,
+	}
+
+	// 5. Call Service Layer
+	err = h.service.CreateChecklistItem(r.Context(), task, item)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to create checklist item")
+		return
+	}
+
+	// 6. Send Response with the new version of the task
+	utils.SetETag(w, task.Version)
+	utils.RespondWithJSON(w, http.StatusCreated, checklistItemToResponse(item))
+}
+
+// updateChecklistItem handles PUT /tasks/:id/checklist/:itemId
+func (h *TaskHandler) updateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	taskID, itemID, ok := parseChecklistURL(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Parse Request Body
+	var req UpdateChecklistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. Get Existing Item
+	item, err := h.service.GetChecklistItem(r.Context(), taskID, itemID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get checklist item")
+		return
+	}
+
+	// 5. Update Fields
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Completed != nil {
+		item.Completed = *req.Completed
+	}
+
+	// 6. Call Service Layer to Update
+	err = h.service.UpdateChecklistItem(r.Context(), task, item)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to update checklist item")
+		return
+	}
+
+	// 7. Send Response with the new version of the task
+	utils.SetETag(w, task.Version)
+	utils.RespondWithJSON(w, http.StatusOK, checklistItemToResponse(item))
+}
+
+// deleteChecklistItem handles DELETE /tasks/:id/checklist/:itemId
+func (h *TaskHandler) deleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	taskID, itemID, ok := parseChecklistURL(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 3. Call Service Layer to Delete Item
+	err = h.service.DeleteChecklistItem(r.Context(), task, itemID)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to delete checklist item")
+		return
+	}
+
+	// 4. Send No Content Response with the new version of the task
+	utils.SetETag(w, task.Version)
+	utils.RespondWithNoContent(w)
+}
+
+// reorderChecklist handles PUT /tasks/:id/checklist/reorder
+func (h *TaskHandler) reorderChecklist(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req ReorderChecklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. Call Service Layer to Rewrite the Positions
+	err = h.service.ReorderChecklist(r.Context(), task, req.ItemIds)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to reorder checklist")
+		return
+	}
+
+	// 5. Respond with the Task and its Reordered Checklist
+	h.getTaskById(w, r)
+}
+
+// addDependency handles POST /tasks/:id/dependencies
+func (h *TaskHandler) addDependency(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse ID from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+
+	// 2. Parse Request Body
+	var req AddDependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// 3. Get the Dependent Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 4. Call Service Layer
+	err = h.service.AddDependency(r.Context(), task, req.BlockedByTaskId)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to add dependency")
+		return
+	}
+
+	// 5. Respond with the Task and its Blockers
+	h.getTaskById(w, r)
+}
+
+// removeDependency handles DELETE /tasks/:id/dependencies/:blockerId
+func (h *TaskHandler) removeDependency(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse IDs from URL
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return
+	}
+	blockerID, err := uuid.Parse(chi.URLParam(r, "blockerId"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid blocking task ID")
+		return
+	}
+
+	// 2. Get Task and check it is the version the client saw
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	if !ifMatchTask(w, r, task) {
+		return
+	}
+
+	// 3. Call Service Layer to Remove the Dependency
+	err = h.service.RemoveDependency(r.Context(), task, blockerID)
+	if err != nil {
+		h.respondWithWriteError(w, r, taskID, err, "Failed to remove dependency")
+		return
+	}
+
+	// 4. Send No Content Response with the new version of the task
+	utils.SetETag(w, task.Version)
+	utils.RespondWithNoContent(w)
+}
+
+// parseChecklistURL parses the task and item ID from the URL and responds with 400 if one is invalid
+func parseChecklistURL(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid task ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	itemID, err := uuid.Parse(chi.URLParam(r, "itemId"))
+	if err != nil {
+		utils.RespondWithBadRequest(w, "Invalid checklist item ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskID, itemID, true
+}
+
+// parseTaskFilter reads the list filter from the query string
+func parseTaskFilter(query url.Values) (TaskFilter, error) {
+	var filter TaskFilter
+
+	if v := query.Get("priority"); v != "" {
+		priority := Priority(v)
+		filter.Priority = &priority
+	}
+	if v := query.Get("domain"); v != "" {
+		domain := Domain(v)
+		filter.Domain = &domain
+	}
+	if v := query.Get("is_backlog"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "is_backlog", "invalid is_backlog value")
+		}
+		filter.IsBacklog = &parsed
+	}
+	if v := query.Get("completed"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "completed", "invalid completed value")
+		}
+		filter.Completed = &parsed
+	}
+	if v := query.Get("blocked"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "blocked", "invalid blocked value")
+		}
+		filter.Blocked = &parsed
+	}
+	if v := query.Get("deadline_from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "deadline_from", "invalid deadline_from format")
+		}
+		filter.DeadlineFrom = &parsed
+	}
+	if v := query.Get("deadline_to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "deadline_to", "invalid deadline_to format")
+		}
+		filter.DeadlineTo = &parsed
+	}
+	if v := query.Get("project_id"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "project_id", "invalid project_id")
+		}
+		filter.ProjectId = &parsed
+	}
+	if v := query.Get("phase_id"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "phase_id", "invalid phase_id")
+		}
+		filter.PhaseId = &parsed
+	}
+	for _, v := range query["tag_id"] {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			return filter, errorutils.Validation("invalid_parameter", "tag_id", "invalid tag_id")
+		}
+		filter.TagIds = append(filter.TagIds, parsed)
+	}
+
+	filter.SortBy = SortField(query.Get("sort"))
+	switch query.Get("order") {
+	case "", "asc":
+		filter.SortDesc = false
+	case "desc":
+		filter.SortDesc = true
 	default:
+		return filter, errorutils.Validation("invalid_parameter", "order", "invalid order value")
+	}
+
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			return filter, errorutils.ErrInvalidLimit
+		}
+		filter.Limit = parsed
+	}
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := decodeCursor(v)
+		if err != nil {
+			return filter, errorutils.ErrInvalidCursor
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// encodeCursor turns a cursor into an opaque URL-safe token
+func encodeCursor(cursor *TaskCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses a token created by encodeCursor
+func decodeCursor(token string) (*TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor TaskCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// recurrenceFromRequest converts the recurrence DTO to its entity
+func recurrenceFromRequest(req *RecurrenceRequest) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{
+		Frequency: req.Frequency,
+		Interval:  req.Interval,
+		ByWeekday: req.ByWeekday,
+		Count:     req.Count,
+	}
+
+	if req.Until != nil {
+		parsed, err := time.Parse("2006-01-02", *req.Until)
+		if err != nil {
+			return nil, errorutils.Validation("invalid_parameter", "until", "invalid until format")
+		}
+		rule.Until = &parsed
+	}
+
+	return rule, nil
+}
+
+// checklistItemToResponse converts ChecklistItem entity to response DTO
+func checklistItemToResponse(item *ChecklistItem) *ChecklistItemResponse {
+	return &ChecklistItemResponse{
+		ItemId:    item.ItemId,
+		TaskId:    item.TaskId,
+		Title:     item.Title,
+		Completed: item.Completed,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+// ifMatchTask checks the If-Match header of r against the version of task.
+// Without the header it answers 428, on a mismatch 412 with the task, and
+// returns false.
+func ifMatchTask(w http.ResponseWriter, r *http.Request, task *Task) bool {
+	if !utils.HasIfMatch(r) {
+		utils.RespondWithProblem(w, r, errorutils.ErrIfMatchRequired, "If-Match is required")
 		return false
 	}
+	if utils.IfMatch(r, utils.ETag(task.Version)) {
+		return true
+	}
+	utils.RespondWithPreconditionFailed(w, task.Version, taskToResponse(task))
+	return false
+}
+
+// respondWithWriteError answers err of a write to the task. When the task
+// changed concurrently the answer is 412 with the task as it is now.
+func (h *TaskHandler) respondWithWriteError(w http.ResponseWriter, r *http.Request, taskID uuid.UUID, err error, message string) {
+	if !errors.Is(err, errorutils.ErrTaskModified) {
+		utils.RespondWithProblem(w, r, err, message)
+		return
+	}
+
+	task, err := h.service.GetTaskById(r.Context(), taskID)
+	if err != nil {
+		utils.RespondWithProblem(w, r, err, "Failed to get task")
+		return
+	}
+	utils.RespondWithPreconditionFailed(w, task.Version, taskToResponse(task))
+}
+
+// taskToDocument returns the writable fields of task as the document a merge patch applies to
+func taskToDocument(task *Task) UpdateTaskRequest {
+	document := UpdateTaskRequest{
+		Title:       &task.Title,
+		Description: task.Description,
+		Priority:    &task.Priority,
+		Domain:      &task.Domain,
+		ProjectId:   task.ProjectId,
+		PhaseId:     task.PhaseId,
+		UniModuleId: task.UniModuleId,
+		IsBacklog:   &task.IsBacklog,
+		Completed:   &task.Completed,
+		TagIds:      &task.TagIds,
+	}
+	if task.Deadline != nil {
+		deadline := task.Deadline.Format("2006-01-02")
+		document.Deadline = &deadline
+	}
+	return document
 }
 
 // taskToResponse converts Task entity to response DTO
 func taskToResponse(task *Task) TaskResponse {
-	return TaskResponse{
+	response := TaskResponse{
 		TaskId:      task.TaskId,
 		Title:       task.Title,
 		Description: task.Description,
 		Priority:    task.Priority,
 		Domain:      task.Domain,
+		ProjectId:   task.ProjectId,
+		PhaseId:     task.PhaseId,
+		UniModuleId: task.UniModuleId,
 		Deadline:    task.Deadline,
 		IsBacklog:   task.IsBacklog,
 		Completed:   task.Completed,
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
+		Version:     task.Version,
+
+		RecurrenceId: task.RecurrenceId,
+
+		ChecklistTotal:     task.ChecklistTotal,
+		ChecklistCompleted: task.ChecklistCompleted,
+
+		Blocked:   task.Blocked,
+		BlockedBy: task.BlockedBy,
+
+		TagIds: task.TagIds,
+	}
+
+	if task.ChecklistTotal > 0 {
+		response.ChecklistRatio = float64(task.ChecklistCompleted) / float64(task.ChecklistTotal)
+	}
+	for _, item := range task.Checklist {
+		response.Checklist = append(response.Checklist, checklistItemToResponse(item))
+	}
+
+	if rule := task.Recurrence; rule != nil {
+		response.Recurrence = &RecurrenceResponse{
+			RecurrenceId: rule.RecurrenceId,
+			Frequency:    rule.Frequency,
+			Interval:     rule.Interval,
+			ByWeekday:    rule.ByWeekday,
+			StartsAt:     rule.StartsAt,
+			Until:        rule.Until,
+			Count:        rule.Count,
+			Occurrences:  rule.Occurrences,
+		}
 	}
+
+	return response
 }
 
 // RegisterRoutes registers all task-related routes
@@ -319,12 +1212,33 @@ func RegisterRoutes(r chi.Router, handler *TaskHandler) {
 		r.Get("/{id}", handler.getTaskById) // GET /tasks/:id
 
 		// Update
-		r.Put("/{id}", handler.updateTask) // PUT /tasks/:id
+		r.Put("/{id}", handler.updateTask)  // PUT /tasks/:id
+		r.Patch("/{id}", handler.patchTask) // PATCH /tasks/:id
 
 		// Delete
 		r.Delete("/{id}", handler.deleteTask) // DELETE /tasks/:id
 
 		// Special operations
 		r.Patch("/{id}/toggle", handler.toggleTaskStatus) // PATCH /tasks/:id/toggle
+
+		// Recurrence
+		r.Put("/{id}/recurrence", handler.setRecurrence)              // PUT /tasks/:id/recurrence
+		r.Delete("/{id}/recurrence", handler.deleteRecurrence)        // DELETE /tasks/:id/recurrence
+		r.Get("/{id}/recurrence/preview", handler.previewOccurrences) // GET /tasks/:id/recurrence/preview
+		r.Post("/{id}/recurrence/skip", handler.skipOccurrence)       // POST /tasks/:id/recurrence/skip
+
+		// Checklist
+		r.Post("/{id}/checklist", handler.createChecklistItem)            // POST /tasks/:id/checklist
+		r.Put("/{id}/checklist/reorder", handler.reorderChecklist)        // PUT /tasks/:id/checklist/reorder
+		r.Put("/{id}/checklist/{itemId}", handler.updateChecklistItem)    // PUT /tasks/:id/checklist/:itemId
+		r.Delete("/{id}/checklist/{itemId}", handler.deleteChecklistItem) // DELETE /tasks/:id/checklist/:itemId
+
+		// Dependencies
+		r.Post("/{id}/dependencies", handler.addDependency)                  // POST /tasks/:id/dependencies
+		r.Delete("/{id}/dependencies/{blockerId}", handler.removeDependency) // DELETE /tasks/:id/dependencies/:blockerId
 	})
+
+	// Tasks of a project or one of its phases
+	r.Get("/projects/{id}/tasks", handler.getProjectTasks)                // GET /projects/:id/tasks
+	r.Get("/projects/{id}/phases/{phaseId}/tasks", handler.getPhaseTasks) // GET /projects/:id/phases/:phaseId/tasks
 }
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// fakeTaskService keeps tasks in memory and fails every call with err when
+// it is set. Methods the tests don't need panic through the nil interface.
+type fakeTaskService struct {
+	TaskServiceInterface
+	tasks map[uuid.UUID]*Task
+	err   error
+}
+
+func newFakeTaskService(tasks ...*Task) *fakeTaskService {
+	service := &fakeTaskService{tasks: make(map[uuid.UUID]*Task)}
+	for _, task := range tasks {
+		service.tasks[task.TaskId] = task
+	}
+	return service
+}
+
+func (s *fakeTaskService) GetTaskById(ctx context.Context, taskid uuid.UUID) (*Task, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	task, ok := s.tasks[taskid]
+	if !ok {
+		return nil, errorutils.ErrTaskNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (s *fakeTaskService) DeleteTask(ctx context.Context, taskid uuid.UUID, version int) error {
+	task, ok := s.tasks[taskid]
+	if !ok {
+		return errorutils.ErrTaskNotFound
+	}
+	if task.Version != version {
+		return errorutils.ErrTaskModified
+	}
+	delete(s.tasks, taskid)
+	return nil
+}
+
+func (s *fakeTaskService) ToggleStatus(ctx context.Context, taskid uuid.UUID, version int) error {
+	task, ok := s.tasks[taskid]
+	if !ok {
+		return errorutils.ErrTaskNotFound
+	}
+	if task.Version != version {
+		return errorutils.ErrTaskModified
+	}
+	task.Completed = !task.Completed
+	task.Version++
+	return nil
+}
+
+// serveTask sends the request through the task routes backed by service
+func serveTask(service TaskServiceInterface, method string, target string, header http.Header) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	RegisterRoutes(r, NewTaskHandler(service))
+
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTaskByIdMissing(t *testing.T) {
+	rec := serveTask(newFakeTaskService(), http.MethodGet, "/tasks/"+uuid.NewString(), nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetTaskByIdServiceError(t *testing.T) {
+	service := newFakeTaskService()
+	service.err = errors.New("connection refused")
+
+	rec := serveTask(service, http.MethodGet, "/tasks/"+uuid.NewString(), nil)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	task := &Task{TaskId: uuid.New(), Title: "Buy milk", Version: 3}
+	service := newFakeTaskService(task)
+
+	rec := serveTask(service, http.MethodDelete, "/tasks/"+task.TaskId.String(), http.Header{"If-Match": {`"3"`}})
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if _, ok := service.tasks[task.TaskId]; ok {
+		t.Fatal("task wasn't deleted")
+	}
+}
+
+func TestDeleteTaskMissing(t *testing.T) {
+	rec := serveTask(newFakeTaskService(), http.MethodDelete, "/tasks/"+uuid.NewString(), nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestDeleteTaskWithoutIfMatch(t *testing.T) {
+	task := &Task{TaskId: uuid.New(), Title: "Buy milk", Version: 3}
+	service := newFakeTaskService(task)
+
+	rec := serveTask(service, http.MethodDelete, "/tasks/"+task.TaskId.String(), nil)
+
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionRequired)
+	}
+	if _, ok := service.tasks[task.TaskId]; !ok {
+		t.Fatal("delete without If-Match removed the task")
+	}
+}
+
+func TestDeleteTaskStaleVersion(t *testing.T) {
+	task := &Task{TaskId: uuid.New(), Title: "Buy milk", Version: 3}
+	service := newFakeTaskService(task)
+
+	rec := serveTask(service, http.MethodDelete, "/tasks/"+task.TaskId.String(), http.Header{"If-Match": {`"2"`}})
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"3"` {
+		t.Fatalf("ETag = %s, want %q", etag, `"3"`)
+	}
+	if _, ok := service.tasks[task.TaskId]; !ok {
+		t.Fatal("stale delete removed the task")
+	}
+}
+
+func TestToggleTaskStatus(t *testing.T) {
+	task := &Task{TaskId: uuid.New(), Title: "Buy milk", Version: 1}
+	service := newFakeTaskService(task)
+
+	rec := serveTask(service, http.MethodPatch, "/tasks/"+task.TaskId.String()+"/toggle", http.Header{"If-Match": {`"1"`}})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var response TaskResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !response.Completed {
+		t.Fatal("toggled task isn't completed")
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"2"` {
+		t.Fatalf("ETag = %s, want %q", etag, `"2"`)
+	}
+}
+
+func TestToggleTaskStatusMissing(t *testing.T) {
+	rec := serveTask(newFakeTaskService(), http.MethodPatch, "/tasks/"+uuid.NewString()+"/toggle", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -2,24 +2,61 @@ package task
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type TaskRepositoryInterface interface {
 	Create(ctx context.Context, task *Task) error
-	Update(ctx context.Context, task *Task) error
+	Update(ctx context.Context, task *Task, completion *Completion) error
 	GetById(ctx context.Context, taskid uuid.UUID) (*Task, error)
-	GetAll(ctx context.Context) ([]*Task, error)
-	Delete(ctx context.Context, taskid uuid.UUID) error
-	ToggleStatus(ctx context.Context, taskid uuid.UUID) error
+	List(ctx context.Context, filter TaskFilter) (*TaskPage, error)
+	Delete(ctx context.Context, taskid uuid.UUID, version int) error
+	ToggleStatus(ctx context.Context, taskid uuid.UUID, version int, completion *Completion) error
+	ProjectExists(ctx context.Context, projectId uuid.UUID) (bool, error)
+	PhaseBelongsToProject(ctx context.Context, phaseId uuid.UUID, projectId uuid.UUID) (bool, error)
+
+	GetRecurrence(ctx context.Context, recurrenceId uuid.UUID) (*RecurrenceRule, error)
+	SetRecurrence(ctx context.Context, task *Task) error
+	DeleteRecurrence(ctx context.Context, task *Task) error
+	SkipOccurrence(ctx context.Context, task *Task) error
+
+	GetChecklist(ctx context.Context, taskid uuid.UUID) ([]*ChecklistItem, error)
+	GetChecklistItem(ctx context.Context, taskid uuid.UUID, itemid uuid.UUID) (*ChecklistItem, error)
+	CreateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error
+	UpdateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, task *Task, itemid uuid.UUID) error
+	ReorderChecklist(ctx context.Context, task *Task, itemids []uuid.UUID) error
+
+	AddDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error
+	RemoveDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error
+	GetBlockerIds(ctx context.Context, taskids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	HasDependencyDeadlineConflict(ctx context.Context, taskid uuid.UUID, deadline time.Time) (bool, error)
+
+	FindUnknownTagIds(ctx context.Context, tagIds []uuid.UUID) ([]uuid.UUID, error)
+	LookupDomain(ctx context.Context, slug Domain) (bool, bool, error)
 }
 
 type TaskServiceInterface interface {
 	CreateTask(ctx context.Context, task *Task) error
 	UpdateTask(ctx context.Context, task *Task) error
 	GetTaskById(ctx context.Context, taskid uuid.UUID) (*Task, error)
-	GetAllTasks(ctx context.Context) ([]*Task, error)
-	DeleteTask(ctx context.Context, taskid uuid.UUID) error
-	ToggleStatus(ctx context.Context, taskid uuid.UUID) error
+	ListTasks(ctx context.Context, filter TaskFilter) (*TaskPage, error)
+	DeleteTask(ctx context.Context, taskid uuid.UUID, version int) error
+	ToggleStatus(ctx context.Context, taskid uuid.UUID, version int) error
+
+	SetRecurrence(ctx context.Context, task *Task, rule *RecurrenceRule) error
+	DeleteRecurrence(ctx context.Context, task *Task) error
+	PreviewOccurrences(ctx context.Context, task *Task, n int) ([]time.Time, error)
+	SkipOccurrence(ctx context.Context, task *Task) error
+
+	GetChecklistItem(ctx context.Context, taskid uuid.UUID, itemid uuid.UUID) (*ChecklistItem, error)
+	CreateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error
+	UpdateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, task *Task, itemid uuid.UUID) error
+	ReorderChecklist(ctx context.Context, task *Task, itemids []uuid.UUID) error
+
+	AddDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error
+	RemoveDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error
 }
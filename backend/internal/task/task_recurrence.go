@@ -0,0 +1,99 @@
+package task
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// nextOccurrences returns up to n deadlines following after, honoring the
+// until date and the maximum number of occurrences of the rule.
+func nextOccurrences(rule *RecurrenceRule, after time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	created := rule.Occurrences
+	for len(occurrences) < n {
+		if rule.Count != nil && created >= *rule.Count {
+			break
+		}
+
+		next := nextOccurrence(rule, after)
+		if rule.Until != nil && next.After(*rule.Until) {
+			break
+		}
+
+		occurrences = append(occurrences, next)
+		after = next
+		created++
+	}
+	return occurrences
+}
+
+// nextOccurrence returns the first deadline of the rule strictly after after
+func nextOccurrence(rule *RecurrenceRule, after time.Time) time.Time {
+	after = after.UTC()
+	anchor := rule.StartsAt.UTC()
+
+	switch rule.Frequency {
+	case FrequencyWeekly:
+		return nextWeekly(rule, anchor, after)
+	case FrequencyMonthly:
+		return nextMonthly(anchor, after, rule.Interval)
+	case FrequencyYearly:
+		return nextMonthly(anchor, after, 12*rule.Interval)
+	default:
+		return after.AddDate(0, 0, rule.Interval)
+	}
+}
+
+// nextWeekly walks day by day until it hits a selected weekday in a week
+// that is a multiple of the interval away from the week of the anchor.
+// Without selected weekdays the weekday of the anchor is used.
+func nextWeekly(rule *RecurrenceRule, anchor time.Time, after time.Time) time.Time {
+	weekdays := rule.ByWeekday
+	if len(weekdays) == 0 {
+		weekdays = []int{int(anchor.Weekday())}
+	}
+
+	anchorWeek := weekStart(anchor)
+	for day := 1; ; day++ {
+		candidate := after.AddDate(0, 0, day)
+		if !slices.Contains(weekdays, int(candidate.Weekday())) {
+			continue
+		}
+
+		weeks := int(math.Round(weekStart(candidate).Sub(anchorWeek).Hours()/24)) / 7
+		if weeks%rule.Interval == 0 {
+			return candidate
+		}
+	}
+}
+
+// nextMonthly returns the first anchor + k*months after after. Days that don't
+// exist in the target month are clamped, so the 31st becomes the 30th in April.
+func nextMonthly(anchor time.Time, after time.Time, months int) time.Time {
+	elapsed := (after.Year()-anchor.Year())*12 + int(after.Month()-anchor.Month())
+	k := max(elapsed/months, 0)
+	for {
+		candidate := addMonthsClamped(anchor, k*months)
+		if candidate.After(after) {
+			return candidate
+		}
+		k++
+	}
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	month := int(t.Month()) - 1 + months
+	year := t.Year() + month/12
+	month = month%12 + 1
+
+	// Day 0 of the following month is the last day of this month
+	lastDay := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(year, time.Month(month), min(t.Day(), lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// weekStart returns midnight of the Monday of the week t is in
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,152 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+// day returns midnight UTC of the date
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestNextOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		after time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name:  "daily every other day",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 2, StartsAt: day(2026, 1, 1)},
+			after: day(2026, 1, 1),
+			n:     3,
+			want:  []time.Time{day(2026, 1, 3), day(2026, 1, 5), day(2026, 1, 7)},
+		},
+		{
+			name:  "monthly on the 31st clamps to the end of shorter months",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, StartsAt: day(2026, 1, 31)},
+			after: day(2026, 1, 31),
+			n:     4,
+			want:  []time.Time{day(2026, 2, 28), day(2026, 3, 31), day(2026, 4, 30), day(2026, 5, 31)},
+		},
+		{
+			name:  "monthly on the 31st clamps to Feb 29 in leap years",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, StartsAt: day(2028, 1, 31)},
+			after: day(2028, 1, 31),
+			n:     2,
+			want:  []time.Time{day(2028, 2, 29), day(2028, 3, 31)},
+		},
+		{
+			name:  "yearly on Feb 29 falls back to Feb 28 outside leap years",
+			rule:  RecurrenceRule{Frequency: FrequencyYearly, Interval: 1, StartsAt: day(2024, 2, 29)},
+			after: day(2024, 2, 29),
+			n:     4,
+			want:  []time.Time{day(2025, 2, 28), day(2026, 2, 28), day(2027, 2, 28), day(2028, 2, 29)},
+		},
+		{
+			name:  "weekly on Monday and Wednesday every other week",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 2, ByWeekday: []int{1, 3}, StartsAt: day(2026, 1, 5)},
+			after: day(2026, 1, 5),
+			n:     5,
+			want:  []time.Time{day(2026, 1, 7), day(2026, 1, 19), day(2026, 1, 21), day(2026, 2, 2), day(2026, 2, 4)},
+		},
+		{
+			name:  "weekly without weekdays repeats the weekday of the start",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 3, StartsAt: day(2026, 1, 8)},
+			after: day(2026, 1, 8),
+			n:     2,
+			want:  []time.Time{day(2026, 1, 29), day(2026, 2, 19)},
+		},
+		{
+			name:  "stops at the maximum number of occurrences",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, StartsAt: day(2026, 1, 1), Count: intPtr(3), Occurrences: 1},
+			after: day(2026, 1, 1),
+			n:     5,
+			want:  []time.Time{day(2026, 1, 2), day(2026, 1, 3)},
+		},
+		{
+			name:  "stops after the until date",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, StartsAt: day(2026, 1, 1), Until: timePtr(day(2026, 1, 3))},
+			after: day(2026, 1, 1),
+			n:     5,
+			want:  []time.Time{day(2026, 1, 2), day(2026, 1, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextOccurrences(&tt.rule, tt.after, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("nextOccurrences = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("nextOccurrences = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNextMonthly(t *testing.T) {
+	tests := []struct {
+		name   string
+		anchor time.Time
+		after  time.Time
+		months int
+		want   time.Time
+	}{
+		{"before the anchor", day(2026, 3, 15), day(2026, 1, 1), 1, day(2026, 3, 15)},
+		{"on the anchor", day(2026, 3, 15), day(2026, 3, 15), 1, day(2026, 4, 15)},
+		{"clamped day doesn't shift later months", day(2026, 1, 31), day(2026, 2, 28), 1, day(2026, 3, 31)},
+		{"every third month", day(2026, 1, 31), day(2026, 2, 1), 3, day(2026, 4, 30)},
+		{"across the year end", day(2026, 11, 30), day(2026, 12, 1), 2, day(2027, 1, 30)},
+		{"yearly from a leap day", day(2024, 2, 29), day(2027, 3, 1), 12, day(2028, 2, 29)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextMonthly(tt.anchor, tt.after, tt.months)
+			if !got.Equal(tt.want) {
+				t.Fatalf("nextMonthly = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextWeekly(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		after time.Time
+		want  time.Time
+	}{
+		{"later the same week", RecurrenceRule{Interval: 2, ByWeekday: []int{1, 5}}, day(2026, 1, 5), day(2026, 1, 9)},
+		{"skips the weeks in between", RecurrenceRule{Interval: 2, ByWeekday: []int{1, 5}}, day(2026, 1, 9), day(2026, 1, 19)},
+		{"Sunday ends the week of the anchor", RecurrenceRule{Interval: 2, ByWeekday: []int{0, 1}}, day(2026, 1, 5), day(2026, 1, 11)},
+		{"from a day that isn't selected", RecurrenceRule{Interval: 3, ByWeekday: []int{3}}, day(2026, 1, 10), day(2026, 1, 28)},
+		{"without weekdays", RecurrenceRule{Interval: 1}, day(2026, 1, 5), day(2026, 1, 12)},
+	}
+
+	// Every rule starts on Monday, January 5th
+	anchor := day(2026, 1, 5)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextWeekly(&tt.rule, anchor, tt.after)
+			if !got.Equal(tt.want) {
+				t.Fatalf("nextWeekly = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
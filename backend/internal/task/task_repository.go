@@ -2,12 +2,48 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/J0kerul/jokers-hub/internal/auth"
+	"github.com/J0kerul/jokers-hub/internal/database"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// pgForeignKeyViolation is the Postgres error code for a violated foreign key constraint
+const pgForeignKeyViolation = "23503"
+
+// foreignKeyErrors maps the foreign keys the task tables write through to the
+// error of the reference they check
+var foreignKeyErrors = map[string]error{
+	"fk_tasks_project":      errorutils.ErrUnknownProject,
+	"tasks_phase_id_fkey":   errorutils.ErrPhaseNotInProject,
+	"fk_tasks_domain":       errorutils.ErrInvalidDomain,
+	"task_tags_tag_id_fkey": errorutils.ErrUnknownTag,
+}
+
+const taskColumns = `task_id, title, description, priority, domain, project_id, phase_id, uni_module_id, recurrence_id, deadline, is_backlog, completed, created_at, updated_at, version,
+	(SELECT COUNT(*) FROM task_checklist_items c WHERE c.task_id = tasks.task_id) AS checklist_total,
+	(SELECT COUNT(*) FROM task_checklist_items c WHERE c.task_id = tasks.task_id AND c.completed) AS checklist_completed,
+	` + blockedExpression + ` AS blocked,
+	ARRAY(SELECT tt.tag_id FROM task_tags tt WHERE tt.task_id = tasks.task_id) AS tag_ids`
+
+// blockedExpression is true for tasks of the outer query that wait on an open task
+const blockedExpression = `EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.task_id = d.blocked_by_task_id WHERE d.task_id = tasks.task_id AND NOT b.completed)`
+
+const checklistColumns = `item_id, task_id, title, completed, position, created_at, updated_at`
+
+// ownedChecklistTask restricts checklist queries with the task id as $1 and
+// the owner as $2 to tasks of that owner
+const ownedChecklistTask = `EXISTS (SELECT 1 FROM tasks o WHERE o.task_id = $1 AND o.owner_id = $2)`
+
 type TaskRepo struct {
 	db *pgxpool.Pool
 }
@@ -17,123 +53,1122 @@ func NewTaskRepo(db *pgxpool.Pool) *TaskRepo {
 }
 
 func (r *TaskRepo) Create(ctx context.Context, task *Task) error {
-	query := `INSERT INTO tasks (title, description, priority, domain, project_id, uni_module_id, deadline, is_backlog, completed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING task_id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// A recurring task starts its own series
+	if task.Recurrence != nil {
+		err = insertRecurrence(ctx, tx, task.Recurrence)
+		if err != nil {
+			return err
+		}
+		task.RecurrenceId = &task.Recurrence.RecurrenceId
+	}
+
+	err = insertTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Update writes the task and, when it is being completed, applies completion
+// in the same transaction
+func (r *TaskRepo) Update(ctx context.Context, task *Task, completion *Completion) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks SET title=$1, description=$2, priority=$3, domain=$4, project_id=$5, phase_id=$6, uni_module_id=$7, deadline=$8, is_backlog=$9, completed=$10, version=version+1, updated_at=NOW() WHERE task_id=$11 AND owner_id=$12 RETURNING updated_at, version`
+	err = tx.QueryRow(ctx, query,
 		task.Title,
 		task.Description,
 		task.Priority,
 		task.Domain,
 		task.ProjectId,
+		task.PhaseId,
 		task.UniModuleId,
 		task.Deadline,
 		task.IsBacklog,
 		task.Completed,
-	).Scan(&task.TaskId, &task.CreatedAt, &task.UpdatedAt)
+		task.TaskId,
+		ownerId,
+	).Scan(&task.UpdatedAt, &task.Version)
 	if err != nil {
-		return fmt.Errorf("failed to create task: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrTaskNotFound
+		}
+		if refErr := foreignKeyError(err); refErr != nil {
+			return refErr
+		}
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	// Replace the tag associations with the new set
+	_, err = tx.Exec(ctx, `DELETE FROM task_tags WHERE task_id = $1`, task.TaskId)
+	if err != nil {
+		return fmt.Errorf("failed to clear tags of task: %w", err)
+	}
+
+	err = insertTagLinks(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	err = applyCompletion(ctx, tx, task.TaskId, completion)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) GetById(ctx context.Context, taskid uuid.UUID) (*Task, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE task_id=$1 AND owner_id=$2`
+	task, err := scanTask(r.db.QueryRow(ctx, query, taskid, ownerId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get task by id: %w", err)
+	}
+	return task, nil
+}
+
+func (r *TaskRepo) List(ctx context.Context, filter TaskFilter) (*TaskPage, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	addCondition("owner_id = $%d", ownerId)
+
+	if filter.Priority != nil {
+		addCondition("priority = $%d", *filter.Priority)
+	}
+	if filter.Domain != nil {
+		addCondition("domain = $%d", *filter.Domain)
+	}
+	if filter.IsBacklog != nil {
+		addCondition("is_backlog = $%d", *filter.IsBacklog)
+	}
+	if filter.Completed != nil {
+		addCondition("completed = $%d", *filter.Completed)
+	}
+	if filter.DeadlineFrom != nil {
+		addCondition("deadline >= $%d", *filter.DeadlineFrom)
+	}
+	if filter.DeadlineTo != nil {
+		addCondition("deadline <= $%d", *filter.DeadlineTo)
+	}
+	if filter.ProjectId != nil {
+		addCondition("project_id = $%d", *filter.ProjectId)
+	}
+	if filter.PhaseId != nil {
+		addCondition("phase_id = $%d", *filter.PhaseId)
+	}
+	if filter.Blocked != nil {
+		addCondition(blockedExpression+" = $%d", *filter.Blocked)
+	}
+	if len(filter.TagIds) > 0 {
+		addCondition("(SELECT COUNT(*) FROM task_tags tt WHERE tt.task_id = tasks.task_id AND tt.tag_id = ANY($%d)) = "+strconv.Itoa(len(filter.TagIds)), filter.TagIds)
+	}
+
+	sortExpr, sortCast := sortExpression(filter.SortBy)
+	direction, comparator := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	// Keyset pagination: continue strictly after the (sort key, task_id) of the cursor
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.Value, filter.Cursor.TaskId)
+		conditions = append(conditions, fmt.Sprintf("(%s, task_id) %s ($%d::%s, $%d)", sortExpr, comparator, len(args)-1, sortCast, len(args)))
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE ` + strings.Join(conditions, " AND ")
+	query += fmt.Sprintf(` ORDER BY %s %s, task_id %s`, sortExpr, direction, direction)
+
+	// Fetch one extra row to find out whether another page exists
+	args = append(args, filter.Limit+1)
+	query += fmt.Sprintf(` LIMIT $%d`, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+	tasks := make([]*Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	page := &TaskPage{Tasks: tasks}
+	if len(tasks) > filter.Limit {
+		page.Tasks = tasks[:filter.Limit]
+		last := page.Tasks[len(page.Tasks)-1]
+		page.NextCursor = &TaskCursor{
+			SortBy: filter.SortBy,
+			Value:  sortValue(last, filter.SortBy),
+			TaskId: last.TaskId,
+		}
+	}
+
+	return page, nil
+}
+
+func (r *TaskRepo) Delete(ctx context.Context, taskid uuid.UUID, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, taskid, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM tasks WHERE task_id=$1`, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ToggleStatus completes or reopens the task. Completion is applied in the
+// same transaction and is nil when the task is reopened.
+func (r *TaskRepo) ToggleStatus(ctx context.Context, taskid uuid.UUID, version int, completion *Completion) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, taskid, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks SET completed = NOT completed, version=version+1, updated_at=NOW() WHERE task_id=$1`, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to toggle task status: %w", err)
+	}
+
+	err = applyCompletion(ctx, tx, taskid, completion)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// applyCompletion applies the side effects of completing the task inside tx.
+// The task is already locked by the caller.
+func applyCompletion(ctx context.Context, tx pgx.Tx, taskid uuid.UUID, completion *Completion) error {
+	if completion == nil {
+		return nil
+	}
+
+	if completion.CompleteChecklist {
+		_, err := tx.Exec(ctx, `UPDATE task_checklist_items SET completed=TRUE, updated_at=NOW() WHERE task_id=$1 AND NOT completed`, taskid)
+		if err != nil {
+			return fmt.Errorf("failed to complete checklist: %w", err)
+		}
+	}
+
+	if completion.NextOccurrence != nil {
+		// Another occurrence after the completed one means the series already continues
+		query := `SELECT NOT EXISTS (SELECT 1 FROM tasks t JOIN tasks o ON o.recurrence_id = t.recurrence_id AND o.task_id <> t.task_id AND o.deadline > t.deadline WHERE t.task_id = $1)`
+		var latest bool
+		err := tx.QueryRow(ctx, query, taskid).Scan(&latest)
+		if err != nil {
+			return fmt.Errorf("failed to check occurrences: %w", err)
+		}
+		if latest {
+			err = insertOccurrence(ctx, tx, completion.NextOccurrence)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func (r *TaskRepo) Update(ctx context.Context, task *Task) error {
-	query := `UPDATE tasks SET title=$1, description=$2, priority=$3, domain=$4, project_id=$5, uni_module_id=$6, deadline=$7, is_backlog=$8, completed=$9, updated_at=NOW() WHERE task_id=$10 RETURNING updated_at`
-	err := r.db.QueryRow(ctx, query,
+// insertOccurrence creates the next task of a series and counts it towards
+// the maximum of its recurrence
+func insertOccurrence(ctx context.Context, tx pgx.Tx, task *Task) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = insertTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE task_recurrences SET occurrences = occurrences + 1, updated_at=NOW() WHERE recurrence_id=$1 AND `+ownedRecurrence(2), task.RecurrenceId, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to count occurrence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrTaskNotRecurring
+	}
+
+	return nil
+}
+
+// CountStats counts the open, overdue and backlog tasks of every user. It is
+// the only query not scoped to an owner, metrics aren't served per user.
+func (r *TaskRepo) CountStats(ctx context.Context) (*TaskStats, error) {
+	query := `SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE ` + database.OverdueCondition("tasks") + `),
+			COUNT(*) FILTER (WHERE is_backlog)
+		FROM tasks WHERE NOT completed`
+	var stats TaskStats
+	err := r.db.QueryRow(ctx, query).Scan(&stats.Open, &stats.Overdue, &stats.Backlog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count task stats: %w", err)
+	}
+	return &stats, nil
+}
+
+func (r *TaskRepo) FindUnknownTagIds(ctx context.Context, tagIds []uuid.UUID) ([]uuid.UUID, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id FROM unnest($1::uuid[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM tags g WHERE g.tag_id = id AND g.owner_id = $2)`
+	rows, err := r.db.Query(ctx, query, tagIds, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tag ids: %w", err)
+	}
+	defer rows.Close()
+
+	unknown := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tag id: %w", err)
+		}
+		unknown = append(unknown, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return unknown, nil
+}
+
+// LookupDomain reports whether the user has a domain with the slug and whether it is archived
+func (r *TaskRepo) LookupDomain(ctx context.Context, slug Domain) (bool, bool, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return false, false, err
+	}
+
+	var archived bool
+	err = r.db.QueryRow(ctx, `SELECT archived FROM domains WHERE slug = $1 AND owner_id = $2`, slug, ownerId).Scan(&archived)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to look up domain: %w", err)
+	}
+	return true, archived, nil
+}
+
+func (r *TaskRepo) ProjectExists(ctx context.Context, projectId uuid.UUID) (bool, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM projects WHERE project_id = $1 AND owner_id = $2)`, projectId, ownerId).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check project: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *TaskRepo) PhaseBelongsToProject(ctx context.Context, phaseId uuid.UUID, projectId uuid.UUID) (bool, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var belongs bool
+	err = r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM phases WHERE phase_id = $1 AND project_id = $2 AND owner_id = $3)`, phaseId, projectId, ownerId).Scan(&belongs)
+	if err != nil {
+		return false, fmt.Errorf("failed to check phase: %w", err)
+	}
+	return belongs, nil
+}
+
+func (r *TaskRepo) GetRecurrence(ctx context.Context, recurrenceId uuid.UUID) (*RecurrenceRule, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT recurrence_id, frequency, repeat_interval, by_weekday, starts_at, ends_at, max_occurrences, occurrences, created_at, updated_at FROM task_recurrences WHERE recurrence_id=$1 AND ` + ownedRecurrence(2)
+	var rule RecurrenceRule
+	err = r.db.QueryRow(ctx, query, recurrenceId, ownerId).Scan(
+		&rule.RecurrenceId,
+		&rule.Frequency,
+		&rule.Interval,
+		&rule.ByWeekday,
+		&rule.StartsAt,
+		&rule.Until,
+		&rule.Count,
+		&rule.Occurrences,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrTaskNotRecurring
+		}
+		return nil, fmt.Errorf("failed to get recurrence: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *TaskRepo) SetRecurrence(ctx context.Context, task *Task) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	rule := task.Recurrence
+	if task.RecurrenceId == nil {
+		// Start a new series with this task as first occurrence
+		err = insertRecurrence(ctx, tx, rule)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE tasks SET recurrence_id=$1 WHERE task_id=$2`, rule.RecurrenceId, task.TaskId)
+		if err != nil {
+			return fmt.Errorf("failed to link recurrence to task: %w", err)
+		}
+		task.RecurrenceId = &rule.RecurrenceId
+	} else {
+		query := `UPDATE task_recurrences SET frequency=$1, repeat_interval=$2, by_weekday=$3, ends_at=$4, max_occurrences=$5, updated_at=NOW() WHERE recurrence_id=$6 AND ` + ownedRecurrence(7) + ` RETURNING recurrence_id, starts_at, occurrences, created_at, updated_at`
+		err = tx.QueryRow(ctx, query,
+			rule.Frequency,
+			rule.Interval,
+			rule.ByWeekday,
+			rule.Until,
+			rule.Count,
+			*task.RecurrenceId,
+			ownerId,
+		).Scan(&rule.RecurrenceId, &rule.StartsAt, &rule.Occurrences, &rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errorutils.ErrTaskNotRecurring
+			}
+			return fmt.Errorf("failed to update recurrence: %w", err)
+		}
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) DeleteRecurrence(ctx context.Context, task *Task) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	// The other occurrences lose their recurrence as well, so they get a new version too
+	_, err = tx.Exec(ctx, `UPDATE tasks SET version=version+1, updated_at=NOW() WHERE recurrence_id=$1 AND task_id<>$2 AND owner_id=$3`, task.RecurrenceId, task.TaskId, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to bump occurrence versions: %w", err)
+	}
+
+	// Occurrences stay as plain tasks, recurrence_id is set to NULL by the foreign key
+	tag, err := tx.Exec(ctx, `DELETE FROM task_recurrences WHERE recurrence_id=$1 AND `+ownedRecurrence(2), task.RecurrenceId, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurrence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrTaskNotRecurring
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) SkipOccurrence(ctx context.Context, task *Task) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `UPDATE tasks SET deadline=$1, version=version+1, updated_at=NOW() WHERE task_id=$2 RETURNING updated_at, version`, task.Deadline, task.TaskId).Scan(&task.UpdatedAt, &task.Version)
+	if err != nil {
+		return fmt.Errorf("failed to move task to next occurrence: %w", err)
+	}
+
+	// The skipped occurrence still counts towards the maximum
+	tag, err := tx.Exec(ctx, `UPDATE task_recurrences SET occurrences = occurrences + 1, updated_at=NOW() WHERE recurrence_id=$1 AND `+ownedRecurrence(2), task.RecurrenceId, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to count occurrence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrTaskNotRecurring
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) GetChecklist(ctx context.Context, taskid uuid.UUID) ([]*ChecklistItem, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + checklistColumns + ` FROM task_checklist_items WHERE task_id=$1 AND ` + ownedChecklistTask + ` ORDER BY position`
+	rows, err := r.db.Query(ctx, query, taskid, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checklist: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*ChecklistItem, 0)
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return items, nil
+}
+
+func (r *TaskRepo) GetChecklistItem(ctx context.Context, taskid uuid.UUID, itemid uuid.UUID) (*ChecklistItem, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + checklistColumns + ` FROM task_checklist_items WHERE task_id=$1 AND item_id=$3 AND ` + ownedChecklistTask
+	item, err := scanChecklistItem(r.db.QueryRow(ctx, query, taskid, ownerId, itemid))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorutils.ErrChecklistItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// CreateChecklistItem adds item to the checklist of task and bumps the
+// version of the task, like every checklist write
+func (r *TaskRepo) CreateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+	item.TaskId = task.TaskId
+
+	// New items are appended behind the last item of the task
+	query := `INSERT INTO task_checklist_items (task_id, title, completed, position)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM task_checklist_items WHERE task_id = $1))
+		RETURNING item_id, position, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		item.TaskId,
+		item.Title,
+		item.Completed,
+	).Scan(&item.ItemId, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) UpdateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE task_checklist_items SET title=$1, completed=$2, updated_at=NOW() WHERE task_id=$3 AND item_id=$4 RETURNING updated_at`
+	err = tx.QueryRow(ctx, query,
+		item.Title,
+		item.Completed,
+		task.TaskId,
+		item.ItemId,
+	).Scan(&item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrChecklistItemNotFound
+		}
+		return fmt.Errorf("failed to update checklist item: %w", err)
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) DeleteChecklistItem(ctx context.Context, task *Task, itemid uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+	taskid := task.TaskId
+
+	tag, err := tx.Exec(ctx, `DELETE FROM task_checklist_items WHERE item_id=$1 AND task_id=$2`, itemid, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrChecklistItemNotFound
+	}
+
+	// Close the gap the deleted item left behind
+	query := `UPDATE task_checklist_items c SET position = ordered.new_position
+		FROM (SELECT item_id, ROW_NUMBER() OVER (ORDER BY position) - 1 AS new_position FROM task_checklist_items WHERE task_id = $1) ordered
+		WHERE c.item_id = ordered.item_id AND c.position <> ordered.new_position`
+	_, err = tx.Exec(ctx, query, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to compact checklist positions: %w", err)
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) ReorderChecklist(ctx context.Context, task *Task, itemids []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+	taskid := task.TaskId
+
+	var total int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM task_checklist_items WHERE task_id=$1`, taskid).Scan(&total)
+	if err != nil {
+		return fmt.Errorf("failed to count checklist items: %w", err)
+	}
+	if total != len(itemids) {
+		return errorutils.ErrInvalidChecklistReorder
+	}
+
+	// Position of every item is its index in itemids, the unique
+	// (task_id, position) constraint is only checked on commit
+	query := `UPDATE task_checklist_items c SET position = ordered.idx - 1, updated_at = NOW()
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(id, idx)
+		WHERE c.item_id = ordered.id AND c.task_id = $2`
+	tag, err := tx.Exec(ctx, query, itemids, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to reorder checklist: %w", err)
+	}
+	if int(tag.RowsAffected()) != total {
+		return errorutils.ErrInvalidChecklistReorder
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) AddDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	// Adding two edges at once could close a cycle neither check sees, so
+	// new dependencies of the same user are added one after another
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('task_dependencies'), hashtext($1::text))`, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to lock dependencies: %w", err)
+	}
+
+	// The new edge closes a cycle if the blocker already waits on the task
+	var cyclic bool
+	query := `WITH RECURSIVE blockers (task_id) AS (
+			SELECT blocked_by_task_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocked_by_task_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.task_id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE task_id = $2)`
+	err = tx.QueryRow(ctx, query, blockerid, task.TaskId).Scan(&cyclic)
+	if err != nil {
+		return fmt.Errorf("failed to walk dependencies: %w", err)
+	}
+	if cyclic {
+		return errorutils.ErrDependencyCycle
+	}
+
+	// The blocker has to belong to the user as well
+	query = `INSERT INTO task_dependencies (task_id, blocked_by_task_id)
+		SELECT $1, task_id FROM tasks WHERE task_id = $2 AND owner_id = $3
+		ON CONFLICT DO NOTHING`
+	tag, err := tx.Exec(ctx, query, task.TaskId, blockerid, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Either the edge is already there or the blocker isn't a task of the user
+		var exists bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM task_dependencies WHERE task_id=$1 AND blocked_by_task_id=$2)`, task.TaskId, blockerid).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check dependency: %w", err)
+		}
+		if exists {
+			return errorutils.ErrDependencyExists
+		}
+		return errorutils.ErrTaskNotFound
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) RemoveDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The task has to be unchanged since it was read
+	err = lockTaskAt(ctx, tx, task.TaskId, task.Version)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM task_dependencies WHERE task_id=$1 AND blocked_by_task_id=$2`, task.TaskId, blockerid)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorutils.ErrDependencyNotFound
+	}
+
+	err = touchTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TaskRepo) GetBlockerIds(ctx context.Context, taskids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT d.task_id, d.blocked_by_task_id FROM task_dependencies d
+		JOIN tasks t ON t.task_id = d.task_id
+		WHERE d.task_id = ANY($1) AND t.owner_id = $2
+		ORDER BY d.created_at`
+	rows, err := r.db.Query(ctx, query, taskids, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	blockers := make(map[uuid.UUID][]uuid.UUID)
+	for rows.Next() {
+		var taskid, blockerid uuid.UUID
+		if err := rows.Scan(&taskid, &blockerid); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		blockers[taskid] = append(blockers[taskid], blockerid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return blockers, nil
+}
+
+func (r *TaskRepo) HasDependencyDeadlineConflict(ctx context.Context, taskid uuid.UUID, deadline time.Time) (bool, error) {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// A task may not be due before its blockers nor after the tasks it blocks
+	query := `SELECT
+		EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.task_id = d.blocked_by_task_id WHERE d.task_id = $1 AND b.deadline > $2 AND b.owner_id = $3)
+		OR EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks t ON t.task_id = d.task_id WHERE d.blocked_by_task_id = $1 AND t.deadline < $2 AND t.owner_id = $3)`
+	var conflict bool
+	err = r.db.QueryRow(ctx, query, taskid, deadline, ownerId).Scan(&conflict)
+	if err != nil {
+		return false, fmt.Errorf("failed to check dependency deadlines: %w", err)
+	}
+	return conflict, nil
+}
+
+// lockTaskAt locks the task row inside tx and fails with ErrTaskModified
+// unless it is still at version, writes that read the task first use it so
+// they can't overwrite a change they haven't seen. Tasks of other users can't
+// be locked.
+func lockTaskAt(ctx context.Context, tx pgx.Tx, taskid uuid.UUID, version int) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = tx.QueryRow(ctx, `SELECT version FROM tasks WHERE task_id=$1 AND owner_id=$2 FOR UPDATE`, taskid, ownerId).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorutils.ErrTaskNotFound
+		}
+		return fmt.Errorf("failed to lock task: %w", err)
+	}
+	if current != version {
+		return errorutils.ErrTaskModified
+	}
+	return nil
+}
+
+// touchTask bumps the version of the task locked in tx. Changes to its
+// checklist and recurrence are part of the task as clients see it.
+func touchTask(ctx context.Context, tx pgx.Tx, task *Task) error {
+	err := tx.QueryRow(ctx, `UPDATE tasks SET version=version+1, updated_at=NOW() WHERE task_id=$1 RETURNING updated_at, version`, task.TaskId).Scan(&task.UpdatedAt, &task.Version)
+	if err != nil {
+		return fmt.Errorf("failed to bump task version: %w", err)
+	}
+	return nil
+}
+
+// insertTask inserts task for the user of ctx inside tx and fills its generated fields
+func insertTask(ctx context.Context, tx pgx.Tx, task *Task) error {
+	ownerId, err := auth.OwnerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO tasks (title, description, priority, domain, project_id, phase_id, uni_module_id, recurrence_id, deadline, is_backlog, completed, owner_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING task_id, created_at, updated_at, version`
+	err = tx.QueryRow(ctx, query,
 		task.Title,
 		task.Description,
 		task.Priority,
 		task.Domain,
 		task.ProjectId,
+		task.PhaseId,
 		task.UniModuleId,
+		task.RecurrenceId,
 		task.Deadline,
 		task.IsBacklog,
 		task.Completed,
-		task.TaskId,
-	).Scan(&task.UpdatedAt)
+		ownerId,
+	).Scan(&task.TaskId, &task.CreatedAt, &task.UpdatedAt, &task.Version)
 	if err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+		if refErr := foreignKeyError(err); refErr != nil {
+			return refErr
+		}
+		return fmt.Errorf("failed to create task: %w", err)
 	}
 
+	return insertTagLinks(ctx, tx, task)
+}
+
+// insertTagLinks associates every tag id of the task with it inside tx
+func insertTagLinks(ctx context.Context, tx pgx.Tx, task *Task) error {
+	for _, tagId := range task.TagIds {
+		_, err := tx.Exec(ctx, `INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2)`, task.TaskId, tagId)
+		if err != nil {
+			if refErr := foreignKeyError(err); refErr != nil {
+				return refErr
+			}
+			return fmt.Errorf("failed to associate tag with task: %w", err)
+		}
+	}
 	return nil
 }
 
-func (r *TaskRepo) GetById(ctx context.Context, taskid uuid.UUID) (*Task, error) {
-	query := `SELECT task_id, title, description, priority, domain, project_id, uni_module_id, deadline, is_backlog, completed, created_at, updated_at FROM tasks WHERE task_id=$1`
-	t := r.db.QueryRow(ctx, query, taskid)
+// insertRecurrence inserts rule inside tx and fills its generated fields
+func insertRecurrence(ctx context.Context, tx pgx.Tx, rule *RecurrenceRule) error {
+	query := `INSERT INTO task_recurrences (frequency, repeat_interval, by_weekday, starts_at, ends_at, max_occurrences) VALUES ($1, $2, $3, $4, $5, $6) RETURNING recurrence_id, occurrences, created_at, updated_at`
+	err := tx.QueryRow(ctx, query,
+		rule.Frequency,
+		rule.Interval,
+		rule.ByWeekday,
+		rule.StartsAt,
+		rule.Until,
+		rule.Count,
+	).Scan(&rule.RecurrenceId, &rule.Occurrences, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create recurrence: %w", err)
+	}
+
+	return nil
+}
+
+// ownedRecurrence restricts a task_recurrences query to rules that have at
+// least one task of the owner passed as parameter param
+func ownedRecurrence(param int) string {
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM tasks o WHERE o.recurrence_id = task_recurrences.recurrence_id AND o.owner_id = $%d)`, param)
+}
+
+// foreignKeyError returns the error of the reference a violated foreign key
+// checks, or nil if err isn't a known foreign key violation
+func foreignKeyError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgForeignKeyViolation {
+		return nil
+	}
+	return foreignKeyErrors[pgErr.ConstraintName]
+}
+
+// scanTask scans a row selected with taskColumns into a Task
+func scanTask(row pgx.Row) (*Task, error) {
 	var task Task
-	err := t.Scan(
+	err := row.Scan(
 		&task.TaskId,
 		&task.Title,
 		&task.Description,
 		&task.Priority,
 		&task.Domain,
 		&task.ProjectId,
+		&task.PhaseId,
 		&task.UniModuleId,
+		&task.RecurrenceId,
 		&task.Deadline,
 		&task.IsBacklog,
 		&task.Completed,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.Version,
+		&task.ChecklistTotal,
+		&task.ChecklistCompleted,
+		&task.Blocked,
+		&task.TagIds,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get task by id: %w", err)
+		return nil, err
 	}
 	return &task, nil
 }
 
-func (r *TaskRepo) GetAll(ctx context.Context) ([]*Task, error) {
-	query := `SELECT task_id, title, description, priority, domain, project_id, uni_module_id, deadline, is_backlog, completed, created_at, updated_at FROM tasks`
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query tasks: %w", err)
+// sortExpression returns the SQL expression to order by and the type a cursor value is cast to.
+// Tasks without deadline sort as if their deadline lies infinitely far in the future.
+func sortExpression(field SortField) (string, string) {
+	switch field {
+	case SortByPriority:
+		return "priority", "priority_enum"
+	case SortByTitle:
+		return "title", "text"
+	case SortByCreatedAt:
+		return "created_at", "timestamptz"
+	case SortByUpdatedAt:
+		return "updated_at", "timestamptz"
+	default:
+		return "COALESCE(deadline, 'infinity'::timestamptz)", "timestamptz"
 	}
-	defer rows.Close()
-	tasks := make([]*Task, 0)
-	for rows.Next() {
-		var task Task
-		err := rows.Scan(
-			&task.TaskId,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Domain,
-			&task.ProjectId,
-			&task.UniModuleId,
-			&task.Deadline,
-			&task.IsBacklog,
-			&task.Completed,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, &task)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration error: %w", err)
-	}
-	return tasks, nil
 }
 
-func (r *TaskRepo) Delete(ctx context.Context, taskid uuid.UUID) error {
-	query := `DELETE FROM tasks WHERE task_id=$1`
-	_, err := r.db.Exec(ctx, query, taskid)
-	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+// sortValue returns the sort key of a task in the textual form sortExpression casts back
+func sortValue(task *Task, field SortField) string {
+	switch field {
+	case SortByPriority:
+		return string(task.Priority)
+	case SortByTitle:
+		return task.Title
+	case SortByCreatedAt:
+		return task.CreatedAt.Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		return task.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		if task.Deadline == nil {
+			return "infinity"
+		}
+		return task.Deadline.Format(time.RFC3339Nano)
 	}
-
-	return nil
 }
 
-func (r *TaskRepo) ToggleStatus(ctx context.Context, taskid uuid.UUID) error {
-	query := `UPDATE tasks SET completed = NOT completed, updated_at=NOW() WHERE task_id=$1`
-	_, err := r.db.Exec(ctx, query, taskid)
-
+// scanChecklistItem scans a row selected with checklistColumns into a ChecklistItem
+func scanChecklistItem(row pgx.Row) (*ChecklistItem, error) {
+	var item ChecklistItem
+	err := row.Scan(
+		&item.ItemId,
+		&item.TaskId,
+		&item.Title,
+		&item.Completed,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to toggle task status: %w", err)
+		return nil, err
 	}
-
-	return nil
+	return &item, nil
 }
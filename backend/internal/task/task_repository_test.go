@@ -0,0 +1,100 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/J0kerul/jokers-hub/internal/database/dbtest"
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// createTestTask stores a backlog task for the user of ctx
+func createTestTask(t *testing.T, repo *TaskRepo, ctx context.Context, title string) *Task {
+	t.Helper()
+
+	task := &Task{Title: title, Priority: PriorityLow, Domain: "personal", IsBacklog: true}
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	return task
+}
+
+func TestTaskRepoIsolatesOwners(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewTaskRepo(db)
+	alice := dbtest.UserContext(t, db)
+	bob := dbtest.UserContext(t, db)
+
+	task := createTestTask(t, repo, alice, "Alice's task")
+	blocker := createTestTask(t, repo, alice, "Alice's blocker")
+	own := createTestTask(t, repo, bob, "Bob's task")
+
+	if _, err := repo.GetById(bob, task.TaskId); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("GetById = %v, want ErrTaskNotFound", err)
+	}
+
+	changed := *task
+	changed.Title = "Taken over"
+	if err := repo.Update(bob, &changed, nil); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("Update = %v, want ErrTaskNotFound", err)
+	}
+
+	if err := repo.ToggleStatus(bob, task.TaskId, task.Version, nil); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("ToggleStatus = %v, want ErrTaskNotFound", err)
+	}
+
+	if err := repo.Delete(bob, task.TaskId, task.Version); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("Delete = %v, want ErrTaskNotFound", err)
+	}
+
+	page, err := repo.List(bob, TaskFilter{SortBy: SortByCreatedAt, Limit: MaxPageSize})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, listed := range page.Tasks {
+		if listed.TaskId != own.TaskId {
+			t.Errorf("List returned task %s of another user", listed.TaskId)
+		}
+	}
+
+	// Neither linking two foreign tasks nor blocking an own task by a foreign one may work
+	if err := repo.AddDependency(bob, task, blocker.TaskId); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("AddDependency = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.AddDependency(bob, own, task.TaskId); !errors.Is(err, errorutils.ErrTaskNotFound) {
+		t.Errorf("AddDependency = %v, want ErrTaskNotFound", err)
+	}
+	blockers, err := repo.GetBlockerIds(alice, []uuid.UUID{task.TaskId})
+	if err != nil {
+		t.Fatalf("GetBlockerIds failed: %v", err)
+	}
+	if len(blockers[task.TaskId]) != 0 {
+		t.Errorf("another user linked blockers %v", blockers[task.TaskId])
+	}
+	blockers, err = repo.GetBlockerIds(bob, []uuid.UUID{own.TaskId})
+	if err != nil {
+		t.Fatalf("GetBlockerIds failed: %v", err)
+	}
+	if len(blockers[own.TaskId]) != 0 {
+		t.Errorf("task was blocked by another user's task %v", blockers[own.TaskId])
+	}
+
+	// Alice's task is untouched
+	current, err := repo.GetById(alice, task.TaskId)
+	if err != nil {
+		t.Fatalf("GetById of the owner failed: %v", err)
+	}
+	if current.Title != task.Title || current.Completed || current.Version != task.Version {
+		t.Errorf("task was changed by another user: %+v", current)
+	}
+
+	// The owner can link the tasks, but only once
+	if err := repo.AddDependency(alice, task, blocker.TaskId); err != nil {
+		t.Fatalf("AddDependency of the owner failed: %v", err)
+	}
+	if err := repo.AddDependency(alice, task, blocker.TaskId); !errors.Is(err, errorutils.ErrDependencyExists) {
+		t.Errorf("AddDependency = %v, want ErrDependencyExists", err)
+	}
+}
@@ -2,29 +2,40 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+	"github.com/J0kerul/jokers-hub/pkg/validation"
 	"github.com/google/uuid"
 )
 
 type TaskService struct {
-	repo TaskRepositoryInterface
+	repo             TaskRepositoryInterface
+	completionPolicy CompletionPolicy
 }
 
-func NewTaskService(repo TaskRepositoryInterface) *TaskService {
+func NewTaskService(repo TaskRepositoryInterface, completionPolicy CompletionPolicy) *TaskService {
 	return &TaskService{
-		repo: repo,
+		repo:             repo,
+		completionPolicy: completionPolicy,
 	}
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, task *Task) error {
-	// Check for required fields
-	err := checkFields(*task)
+	// Check fields and references
+	task.TagIds = uniqueIds(task.TagIds)
+	err := s.checkFields(ctx, *task)
 	if err != nil {
 		return err
 	}
 
+	// The first deadline anchors the series
+	if task.Recurrence != nil {
+		task.Recurrence.StartsAt = *task.Deadline
+	}
+
 	// Create Task
 	err = s.repo.Create(ctx, task)
 	if err != nil {
@@ -35,17 +46,61 @@ func (s *TaskService) CreateTask(ctx context.Context, task *Task) error {
 }
 
 func (s *TaskService) UpdateTask(ctx context.Context, task *Task) error {
-	// Check for required fields
-	err := checkFields(*task)
+	// Check fields and references
+	task.TagIds = uniqueIds(task.TagIds)
+	err := s.checkFields(ctx, *task)
 	if err != nil {
 		return err
 	}
+
+	// The deadline has to stay between the deadlines of blockers and dependents
+	if task.Deadline != nil {
+		conflict, err := s.repo.HasDependencyDeadlineConflict(ctx, task.TaskId, *task.Deadline)
+		if err != nil {
+			return fmt.Errorf("failed to check dependency deadlines: %w", err)
+		}
+		if conflict {
+			return errorutils.ErrDependencyDeadline
+		}
+	}
+
+	// Completing the task has to respect its open checklist items and
+	// schedules the next occurrence of recurring tasks
+	var completion *Completion
+	if task.Completed {
+		current, err := s.repo.GetById(ctx, task.TaskId)
+		if err != nil {
+			return fmt.Errorf("failed to get current task: %w", err)
+		}
+		if current.Version != task.Version {
+			return errorutils.ErrTaskModified
+		}
+		if !current.Completed {
+			completion, err = s.completionOf(current)
+			if err != nil {
+				return err
+			}
+			completion.NextOccurrence, err = s.nextOccurrenceOf(ctx, task)
+			if err != nil {
+				return fmt.Errorf("failed to schedule next occurrence: %w", err)
+			}
+		}
+	}
+
 	// Update Task
-	err = s.repo.Update(ctx, task)
+	err = s.repo.Update(ctx, task, completion)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
+	// From here on every checklist item is completed
+	if completion != nil && completion.CompleteChecklist {
+		task.ChecklistCompleted = task.ChecklistTotal
+		for _, item := range task.Checklist {
+			item.Completed = true
+		}
+	}
+
 	return nil
 }
 
@@ -60,26 +115,84 @@ func (s *TaskService) GetTaskById(ctx context.Context, taskid uuid.UUID) (*Task,
 		return nil, fmt.Errorf("failed to get task by id: %w", err)
 	}
 
+	// Load the recurrence rule of recurring tasks
+	if task.RecurrenceId != nil {
+		task.Recurrence, err = s.repo.GetRecurrence(ctx, *task.RecurrenceId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recurrence of task: %w", err)
+		}
+	}
+
+	// Load the checklist
+	if task.ChecklistTotal > 0 {
+		task.Checklist, err = s.repo.GetChecklist(ctx, taskid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checklist of task: %w", err)
+		}
+	}
+
+	// Load the tasks blocking this one
+	blockers, err := s.repo.GetBlockerIds(ctx, []uuid.UUID{taskid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies of task: %w", err)
+	}
+	task.BlockedBy = blockers[taskid]
+
 	return task, nil
 }
 
-func (s *TaskService) GetAllTasks(ctx context.Context) ([]*Task, error) {
-	tasks, err := s.repo.GetAll(ctx)
+func (s *TaskService) ListTasks(ctx context.Context, filter TaskFilter) (*TaskPage, error) {
+	// Default to the order the task manager shows tasks in
+	if filter.SortBy == "" {
+		filter.SortBy = SortByDeadline
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+	filter.TagIds = uniqueIds(filter.TagIds)
+
+	// Check filter values
+	err := checkFilter(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all tasks: %w", err)
+		return nil, err
+	}
+
+	// Filtering by domain requires the domain to exist, archived ones included
+	if filter.Domain != nil {
+		found, _, err := s.repo.LookupDomain(ctx, *filter.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check domain: %w", err)
+		}
+		if !found {
+			return nil, errorutils.ErrInvalidDomain
+		}
 	}
 
-	return tasks, nil
+	// Listing the tasks of a project requires the project (and phase) to exist
+	if filter.ProjectId != nil {
+		err = s.checkProjectRefs(ctx, filter.ProjectId, filter.PhaseId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	return page, nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskid uuid.UUID) error {
+// DeleteTask deletes the task if it is still at version
+func (s *TaskService) DeleteTask(ctx context.Context, taskid uuid.UUID, version int) error {
 	// Check if id isn't empty
 	if taskid == uuid.Nil {
 		return errorutils.ErrMissingId
 	}
 
 	// Delete id
-	err := s.repo.Delete(ctx, taskid)
+	err := s.repo.Delete(ctx, taskid, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -87,62 +200,523 @@ func (s *TaskService) DeleteTask(ctx context.Context, taskid uuid.UUID) error {
 	return nil
 }
 
-func (s *TaskService) ToggleStatus(ctx context.Context, taskid uuid.UUID) error {
+// ToggleStatus completes or reopens the task if it is still at version
+func (s *TaskService) ToggleStatus(ctx context.Context, taskid uuid.UUID, version int) error {
 	// Check if id isn't empty
 	if taskid == uuid.Nil {
 		return errorutils.ErrMissingId
 	}
 
-	err := s.repo.ToggleStatus(ctx, taskid)
+	task, err := s.GetTaskById(ctx, taskid)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	// Check the version before the completion policy touches the checklist
+	if task.Version != version {
+		return errorutils.ErrTaskModified
+	}
+
+	// Completing the task has to respect its open checklist items and
+	// schedules the next occurrence of recurring tasks
+	var completion *Completion
+	if !task.Completed {
+		completion, err = s.completionOf(task)
+		if err != nil {
+			return err
+		}
+		completion.NextOccurrence, err = s.nextOccurrenceOf(ctx, task)
+		if err != nil {
+			return fmt.Errorf("failed to schedule next occurrence: %w", err)
+		}
+	}
+
+	err = s.repo.ToggleStatus(ctx, taskid, version, completion)
 	if err != nil {
 		return fmt.Errorf("failed to toggle task status: %w", err)
 	}
+	task.Completed = !task.Completed
+
+	return nil
+}
+
+func (s *TaskService) SetRecurrence(ctx context.Context, task *Task, rule *RecurrenceRule) error {
+	// Check recurrence, only tasks with a deadline can repeat
+	v := validation.New()
+	v.Collect(checkRecurrence(rule))
+	v.Check(task.Deadline != nil, errorutils.ErrRecurrenceNeedsDeadline)
+	err := v.Err()
+	if err != nil {
+		return err
+	}
+
+	rule.StartsAt = *task.Deadline
+	task.Recurrence = rule
+	err = s.repo.SetRecurrence(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to set recurrence: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) DeleteRecurrence(ctx context.Context, task *Task) error {
+	if task.RecurrenceId == nil {
+		return errorutils.ErrTaskNotRecurring
+	}
+
+	err := s.repo.DeleteRecurrence(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurrence: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) PreviewOccurrences(ctx context.Context, task *Task, n int) ([]time.Time, error) {
+	if n < 1 || n > MaxPreviewOccurrences {
+		return nil, errorutils.ErrInvalidPreviewCount
+	}
+
+	if task.Recurrence == nil {
+		return nil, errorutils.ErrTaskNotRecurring
+	}
+
+	return nextOccurrences(task.Recurrence, *task.Deadline, n), nil
+}
+
+func (s *TaskService) SkipOccurrence(ctx context.Context, task *Task) error {
+	if task.Recurrence == nil {
+		return errorutils.ErrTaskNotRecurring
+	}
+
+	// Move the task to the occurrence after the skipped one
+	next := nextOccurrences(task.Recurrence, *task.Deadline, 1)
+	if len(next) == 0 {
+		return errorutils.ErrRecurrenceEnded
+	}
+	task.Deadline = &next[0]
+
+	// The new deadline has to stay between the deadlines of blockers and dependents
+	conflict, err := s.repo.HasDependencyDeadlineConflict(ctx, task.TaskId, *task.Deadline)
+	if err != nil {
+		return fmt.Errorf("failed to check dependency deadlines: %w", err)
+	}
+	if conflict {
+		return errorutils.ErrDependencyDeadline
+	}
+
+	err = s.repo.SkipOccurrence(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to skip occurrence: %w", err)
+	}
+	task.Recurrence.Occurrences++
+
+	return nil
+}
+
+func (s *TaskService) GetChecklistItem(ctx context.Context, taskid uuid.UUID, itemid uuid.UUID) (*ChecklistItem, error) {
+	// Check if ids aren't empty
+	if taskid == uuid.Nil || itemid == uuid.Nil {
+		return nil, errorutils.ErrMissingId
+	}
+
+	item, err := s.repo.GetChecklistItem(ctx, taskid, itemid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+
+	return item, nil
+}
+
+// CreateChecklistItem adds item to the checklist of task if the task is still
+// at its version. Like every checklist write it bumps the version of task.
+func (s *TaskService) CreateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error {
+	// Check for required fields
+	if task.TaskId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+	if err := checkChecklistItem(*item); err != nil {
+		return err
+	}
+
+	err := s.repo.CreateChecklistItem(ctx, task, item)
+	if err != nil {
+		return fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, task *Task, item *ChecklistItem) error {
+	// Check for required fields
+	if task.TaskId == uuid.Nil || item.ItemId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+	if err := checkChecklistItem(*item); err != nil {
+		return err
+	}
+
+	err := s.repo.UpdateChecklistItem(ctx, task, item)
+	if err != nil {
+		return fmt.Errorf("failed to update checklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, task *Task, itemid uuid.UUID) error {
+	// Check if ids aren't empty
+	if task.TaskId == uuid.Nil || itemid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.DeleteChecklistItem(ctx, task, itemid)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) ReorderChecklist(ctx context.Context, task *Task, itemids []uuid.UUID) error {
+	// Check if id isn't empty
+	if task.TaskId == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	// Every item may only appear once in the new order
+	seen := make(map[uuid.UUID]bool, len(itemids))
+	for _, id := range itemids {
+		if id == uuid.Nil || seen[id] {
+			return errorutils.ErrInvalidChecklistReorder
+		}
+		seen[id] = true
+	}
+
+	err := s.repo.ReorderChecklist(ctx, task, itemids)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrInvalidChecklistReorder) {
+			return err
+		}
+		return fmt.Errorf("failed to reorder checklist: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TaskService) AddDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error {
+	// Check if id isn't empty
+	if blockerid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+	if blockerid == task.TaskId {
+		return errorutils.ErrSelfDependency
+	}
+
+	// Check if the blocking task exists
+	blocker, err := s.repo.GetById(ctx, blockerid)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrTaskNotFound) {
+			return errorutils.ErrUnknownBlocker
+		}
+		return fmt.Errorf("failed to get blocking task: %w", err)
+	}
+
+	// A task can't be due before the task blocking it
+	if task.Deadline != nil && blocker.Deadline != nil && task.Deadline.Before(*blocker.Deadline) {
+		return errorutils.ErrDependencyDeadline
+	}
+
+	// The repository refuses edges that would close a cycle
+	err = s.repo.AddDependency(ctx, task, blockerid)
+	if err != nil {
+		if errors.Is(err, errorutils.ErrDependencyCycle) {
+			return err
+		}
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
 
 	return nil
 }
 
-func checkFields(task Task) error {
-	// Check title
-	if task.Title == "" {
-		return errorutils.ErrTitleRequired
+func (s *TaskService) RemoveDependency(ctx context.Context, task *Task, blockerid uuid.UUID) error {
+	// Check if ids aren't empty
+	if task.TaskId == uuid.Nil || blockerid == uuid.Nil {
+		return errorutils.ErrMissingId
+	}
+
+	err := s.repo.RemoveDependency(ctx, task, blockerid)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	return nil
+}
+
+// completionOf returns what completing task changes besides the task. Open
+// checklist items block the completion unless the policy cascades to them.
+func (s *TaskService) completionOf(task *Task) (*Completion, error) {
+	completion := &Completion{}
+	if task.ChecklistCompleted < task.ChecklistTotal {
+		if s.completionPolicy != CompletionCascade {
+			return nil, errorutils.ErrOpenChecklistItems
+		}
+		completion.CompleteChecklist = true
+	}
+
+	return completion, nil
+}
+
+// nextOccurrenceOf returns the task following the completed occurrence, or
+// nil if the task doesn't repeat or its series has ended. The repository only
+// creates it when the series doesn't continue past the task yet.
+func (s *TaskService) nextOccurrenceOf(ctx context.Context, task *Task) (*Task, error) {
+	if task.RecurrenceId == nil || task.Deadline == nil {
+		return nil, nil
+	}
+
+	rule := task.Recurrence
+	if rule == nil {
+		var err error
+		rule, err = s.repo.GetRecurrence(ctx, *task.RecurrenceId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next := nextOccurrences(rule, *task.Deadline, 1)
+	if len(next) == 0 {
+		return nil, nil
+	}
+
+	// The occurrence starts without dependencies, so its deadline can't
+	// conflict with a blocker and needs no dependency check
+	return &Task{
+		Title:        task.Title,
+		Description:  task.Description,
+		Priority:     task.Priority,
+		Domain:       task.Domain,
+		ProjectId:    task.ProjectId,
+		PhaseId:      task.PhaseId,
+		UniModuleId:  task.UniModuleId,
+		RecurrenceId: task.RecurrenceId,
+		TagIds:       task.TagIds,
+		Deadline:     &next[0],
+		IsBacklog:    false,
+		Completed:    false,
+	}, nil
+}
+
+// checkTagIds returns a validation error if one of the ids has no tag
+func (s *TaskService) checkTagIds(ctx context.Context, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	unknown, err := s.repo.FindUnknownTagIds(ctx, tagIds)
+	if err != nil {
+		return fmt.Errorf("failed to check tags: %w", err)
+	}
+
+	if len(unknown) > 0 {
+		return errorutils.ErrUnknownTag
+	}
+	return nil
+}
+
+// checkProjectRefs verifies that the project exists and the phase is one of its phases
+func (s *TaskService) checkProjectRefs(ctx context.Context, projectId *uuid.UUID, phaseId *uuid.UUID) error {
+	if projectId == nil {
+		if phaseId != nil {
+			return errorutils.ErrPhaseWithoutProject
+		}
+		return nil
+	}
+
+	exists, err := s.repo.ProjectExists(ctx, *projectId)
+	if err != nil {
+		return fmt.Errorf("failed to check project: %w", err)
+	}
+	if !exists {
+		return errorutils.ErrUnknownProject
+	}
+
+	if phaseId != nil {
+		belongs, err := s.repo.PhaseBelongsToProject(ctx, *phaseId, *projectId)
+		if err != nil {
+			return fmt.Errorf("failed to check phase: %w", err)
+		}
+		if !belongs {
+			return errorutils.ErrPhaseNotInProject
+		}
+	}
+
+	return nil
+}
+
+// uniqueIds drops repeated ids while keeping the order of first appearance
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// checkFields validates the fields and references of task and returns every
+// violation together
+func (s *TaskService) checkFields(ctx context.Context, task Task) error {
+	v := validation.New()
+
+	// Check title and description
+	v.Required(task.Title, errorutils.ErrTitleRequired)
+	v.MaxLength("title", task.Title, MaxTitleLength)
+	if task.Description != nil {
+		v.MaxLength("description", *task.Description, MaxDescriptionLength)
 	}
 
 	// Check Priority Validation
-	if !isPrioValid(task.Priority) {
-		return errorutils.ErrInvalidPriority
+	v.Check(isPrioValid(task.Priority), errorutils.ErrInvalidPriority)
+
+	// Non-backlog tasks need a deadline, backlog tasks must not have one
+	v.Check(task.Deadline != nil || task.IsBacklog, errorutils.ErrNoDeadlineForNonBacklog)
+	v.Check(!task.IsBacklog || task.Deadline == nil, errorutils.ErrBacklogDeadlineConflict)
+
+	// Recurring tasks are scheduled by their deadline
+	recurring := task.Recurrence != nil || task.RecurrenceId != nil
+	v.Check(!recurring || task.Deadline != nil, errorutils.ErrRecurrenceNeedsDeadline)
+	if task.Recurrence != nil {
+		v.Collect(checkRecurrence(task.Recurrence))
+	}
+
+	// References are checked last, each costs a query
+	if err := v.Collect(s.checkDomain(ctx, task)); err != nil {
+		return err
+	}
+	if err := v.Collect(s.checkProjectRefs(ctx, task.ProjectId, task.PhaseId)); err != nil {
+		return err
+	}
+	if err := v.Collect(s.checkTagIds(ctx, task.TagIds)); err != nil {
+		return err
 	}
 
-	// Check Domain Validation
-	if !isDomainValid(task.Domain) {
+	return v.Err()
+}
+
+// checkDomain checks the domain against the domains table
+func (s *TaskService) checkDomain(ctx context.Context, task Task) error {
+	found, archived, err := s.repo.LookupDomain(ctx, task.Domain)
+	if err != nil {
+		return fmt.Errorf("failed to check domain: %w", err)
+	}
+	if !found {
 		return errorutils.ErrInvalidDomain
 	}
 
-	// Check if deadline exists if task is not marked as backlog
-	if task.Deadline == nil && !task.IsBacklog {
-		return errorutils.ErrNoDeadlineForNonBacklog
+	// Archived domains keep the tasks they have but don't take new ones
+	if archived {
+		if task.TaskId == uuid.Nil {
+			return errorutils.ErrArchivedDomain
+		}
+		current, err := s.repo.GetById(ctx, task.TaskId)
+		if err != nil {
+			return fmt.Errorf("failed to get current task: %w", err)
+		}
+		if current.Domain != task.Domain {
+			return errorutils.ErrArchivedDomain
+		}
+	}
+
+	return nil
+}
+
+// checkRecurrence validates a recurrence rule and normalizes its defaults
+func checkRecurrence(rule *RecurrenceRule) error {
+	v := validation.New()
+
+	switch rule.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		v.Check(false, errorutils.ErrInvalidFrequency)
 	}
 
-	// Check the other way around
-	if task.IsBacklog && task.Deadline != nil {
-		return errorutils.ErrBacklogDeadlineConflict
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	v.Check(rule.Interval > 0, errorutils.ErrInvalidInterval)
 
+	if rule.ByWeekday == nil {
+		rule.ByWeekday = []int{}
+	}
+	validWeekdays := len(rule.ByWeekday) == 0 || rule.Frequency == FrequencyWeekly
+	for _, weekday := range rule.ByWeekday {
+		if weekday < int(time.Sunday) || weekday > int(time.Saturday) {
+			validWeekdays = false
+		}
+	}
+	v.Check(validWeekdays, errorutils.ErrInvalidWeekday)
+
+	// Like in RRULE a series ends either on a date or after a number of occurrences
+	v.Check(rule.Until == nil || rule.Count == nil, errorutils.ErrRecurrenceEndConflict)
+	v.Check(rule.Count == nil || *rule.Count >= 1, errorutils.ErrInvalidOccurrenceCount)
+
+	return v.Err()
+}
+
+// checkChecklistItem validates the title of a checklist item
+func checkChecklistItem(item ChecklistItem) error {
+	v := validation.New()
+	v.Required(item.Title, errorutils.ErrTitleRequired)
+	v.MaxLength("title", item.Title, MaxTitleLength)
+	return v.Err()
+}
+
+func checkFilter(filter TaskFilter) error {
+	if filter.Priority != nil && !isPrioValid(*filter.Priority) {
+		return errorutils.ErrInvalidPriority
+	}
+
+	if filter.DeadlineFrom != nil && filter.DeadlineTo != nil && filter.DeadlineFrom.After(*filter.DeadlineTo) {
+		return errorutils.ErrInvalidDeadlineRange
+	}
+
+	if !isSortFieldValid(filter.SortBy) {
+		return errorutils.ErrInvalidSortField
+	}
+
+	if filter.Limit <= 0 || filter.Limit > MaxPageSize {
+		return errorutils.ErrInvalidLimit
+	}
+
+	// A cursor is only meaningful for the order it was created with
+	if filter.Cursor != nil && filter.Cursor.SortBy != filter.SortBy {
+		return errorutils.ErrInvalidCursor
 	}
 
 	return nil
 }
 
-func isPrioValid(p Priority) bool {
-	switch p {
-	case PriorityHigh, PriorityLow, PriorityMedium:
+func isSortFieldValid(f SortField) bool {
+	switch f {
+	case SortByDeadline, SortByPriority, SortByTitle, SortByCreatedAt, SortByUpdatedAt:
 		return true
 	default:
 		return false
 	}
 }
 
-func isDomainValid(d Domain) bool {
-	switch d {
-	case DomainWork, DomainUniversity, DomainPersonal, DomainCoding, DomainHealth, DomainFinance, DomainSocial, DomainHome, DomainStudy, DomainTravel, DomainAdministration:
+func isPrioValid(p Priority) bool {
+	switch p {
+	case PriorityHigh, PriorityLow, PriorityMedium:
 		return true
 	default:
 		return false
@@ -0,0 +1 @@
+ALTER TABLE phases DROP CONSTRAINT IF EXISTS uq_phases_project_position;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Make existing positions dense per project before enforcing uniqueness
+UPDATE phases p SET position = ordered.new_position
+FROM (
+    SELECT phase_id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY position, created_at) - 1 AS new_position
+    FROM phases
+) ordered
+WHERE p.phase_id = ordered.phase_id;
+
+ALTER TABLE phases
+ADD CONSTRAINT uq_phases_project_position
+UNIQUE (project_id, position) DEFERRABLE INITIALLY DEFERRED;
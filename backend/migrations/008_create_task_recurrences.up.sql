@@ -0,0 +1,17 @@
+CREATE TYPE recurrence_frequency_enum AS ENUM ('daily', 'weekly', 'monthly', 'yearly');
+
+CREATE TABLE IF NOT EXISTS task_recurrences (
+    recurrence_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    frequency recurrence_frequency_enum NOT NULL,
+    repeat_interval INT NOT NULL DEFAULT 1 CHECK (repeat_interval > 0),
+    by_weekday SMALLINT[] NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,
+    max_occurrences INT CHECK (max_occurrences > 0),
+    occurrences INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE tasks
+ADD COLUMN recurrence_id UUID REFERENCES task_recurrences(recurrence_id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS task_checklist_items;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    item_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_checklist_task_position UNIQUE (task_id, position) DEFERRABLE INITIALLY DEFERRED
+);
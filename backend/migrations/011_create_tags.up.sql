@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS tags (
+    tag_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Tag names are unique regardless of case so "Errand" and "errand" can't coexist
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id UUID REFERENCES tasks(task_id) ON DELETE CASCADE,
+    tag_id UUID REFERENCES tags(tag_id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS project_tags (
+    project_id UUID REFERENCES projects(project_id) ON DELETE CASCADE,
+    tag_id UUID REFERENCES tags(tag_id) ON DELETE CASCADE,
+    PRIMARY KEY (project_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_project_tags_tag_id ON project_tags(tag_id);
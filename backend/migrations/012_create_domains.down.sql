@@ -0,0 +1,23 @@
+CREATE TYPE domain_enum AS ENUM (
+    'work',
+    'university',
+    'personal',
+    'coding',
+    'health',
+    'finance',
+    'social',
+    'home',
+    'study',
+    'travel',
+    'administration'
+);
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_tasks_domain;
+
+-- Domains added after the enum have no enum value to go back to
+UPDATE tasks SET domain = 'personal'
+WHERE domain NOT IN ('work', 'university', 'personal', 'coding', 'health', 'finance', 'social', 'home', 'study', 'travel', 'administration');
+
+ALTER TABLE tasks ALTER COLUMN domain TYPE domain_enum USING domain::domain_enum;
+
+DROP TABLE IF EXISTS domains;
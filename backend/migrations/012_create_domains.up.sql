@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS domains (
+    domain_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    icon TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT uq_domains_position UNIQUE (position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Seed the values of the former domain_enum with the colors and icons the frontend used for them
+INSERT INTO domains (slug, display_name, color, icon, position) VALUES
+    ('work', 'Work', '#3B82F6', 'briefcase', 0),
+    ('university', 'University', '#A855F7', 'graduation-cap', 1),
+    ('personal', 'Personal', '#06B6D4', 'user', 2),
+    ('coding', 'Coding', '#64748B', 'code', 3),
+    ('health', 'Health', '#10B981', 'heart', 4),
+    ('finance', 'Finance', '#F59E0B', 'wallet', 5),
+    ('social', 'Social', '#F97316', 'users', 6),
+    ('home', 'Home', '#EF4444', 'home', 7),
+    ('study', 'Study', '#6366F1', 'book-open', 8),
+    ('travel', 'Travel', '#0EA5E9', 'plane', 9),
+    ('administration', 'Administration', '#6B7280', 'settings', 10)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Tasks keep the slug, renaming a slug carries over to its tasks
+ALTER TABLE tasks ALTER COLUMN domain TYPE TEXT USING domain::text;
+
+ALTER TABLE tasks
+ADD CONSTRAINT fk_tasks_domain
+FOREIGN KEY (domain) REFERENCES domains(slug) ON UPDATE CASCADE;
+
+DROP TYPE IF EXISTS domain_enum;
@@ -0,0 +1,12 @@
+ALTER TABLE tech_stack_items DROP CONSTRAINT IF EXISTS uq_tech_stack_items_owner_name;
+ALTER TABLE tech_stack_items ADD CONSTRAINT tech_stack_items_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS idx_tech_stack_items_owner_id;
+DROP INDEX IF EXISTS idx_phases_owner_id;
+DROP INDEX IF EXISTS idx_projects_owner_id;
+DROP INDEX IF EXISTS idx_tasks_owner_id;
+
+ALTER TABLE tech_stack_items DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE phases DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,20 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE phases ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE tech_stack_items ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+
+-- Rows from before accounts existed belong to the oldest user. A database
+-- without users keeps them unowned until the first user is created and adopts them.
+UPDATE tasks SET owner_id = (SELECT user_id FROM users ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+UPDATE projects SET owner_id = (SELECT user_id FROM users ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+UPDATE phases ph SET owner_id = p.owner_id FROM projects p WHERE p.project_id = ph.project_id AND ph.owner_id IS NULL;
+UPDATE tech_stack_items SET owner_id = (SELECT user_id FROM users ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_id ON tasks(owner_id);
+CREATE INDEX IF NOT EXISTS idx_projects_owner_id ON projects(owner_id);
+CREATE INDEX IF NOT EXISTS idx_phases_owner_id ON phases(owner_id);
+CREATE INDEX IF NOT EXISTS idx_tech_stack_items_owner_id ON tech_stack_items(owner_id);
+
+-- Every user keeps their own tech stack catalogue
+ALTER TABLE tech_stack_items DROP CONSTRAINT IF EXISTS tech_stack_items_name_key;
+ALTER TABLE tech_stack_items ADD CONSTRAINT uq_tech_stack_items_owner_name UNIQUE (owner_id, name);
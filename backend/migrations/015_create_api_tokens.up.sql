@@ -0,0 +1,18 @@
+-- Personal access tokens for scripts. Like refresh tokens only the sha256 of
+-- the token is stored, token_prefix keeps the start of it so users can tell
+-- their tokens apart.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT chk_api_tokens_scopes CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['read', 'tasks:write', 'projects:write'])
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
@@ -0,0 +1,4 @@
+-- Every change of a task or project bumps its version, clients send it back
+-- in If-Match so concurrent edits can't silently overwrite each other
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+ALTER TABLE tech_stack_items DROP CONSTRAINT IF EXISTS uq_tech_stack_items_owner_position;
@@ -0,0 +1,11 @@
+-- Make existing positions dense per owner before enforcing uniqueness
+UPDATE tech_stack_items t SET position = ordered.new_position
+FROM (
+    SELECT tech_stack_item_id, ROW_NUMBER() OVER (PARTITION BY owner_id ORDER BY position, name) - 1 AS new_position
+    FROM tech_stack_items
+) ordered
+WHERE t.tech_stack_item_id = ordered.tech_stack_item_id;
+
+ALTER TABLE tech_stack_items
+ADD CONSTRAINT uq_tech_stack_items_owner_position
+UNIQUE (owner_id, position) DEFERRABLE INITIALLY DEFERRED;
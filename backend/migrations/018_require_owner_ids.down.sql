@@ -0,0 +1,4 @@
+ALTER TABLE tech_stack_items ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE phases ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE projects ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE tasks ALTER COLUMN owner_id DROP NOT NULL;
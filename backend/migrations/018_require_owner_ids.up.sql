@@ -0,0 +1,17 @@
+-- Unowned rows only exist in databases that had no users when migration 014
+-- ran. The first user adopts them, so create it before applying this migration:
+--   server migrate to 17 && server user create <email> && server migrate up
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM tasks WHERE owner_id IS NULL)
+        OR EXISTS (SELECT 1 FROM projects WHERE owner_id IS NULL)
+        OR EXISTS (SELECT 1 FROM phases WHERE owner_id IS NULL)
+        OR EXISTS (SELECT 1 FROM tech_stack_items WHERE owner_id IS NULL) THEN
+        RAISE EXCEPTION 'rows without owner left, create the first user with "server user create" before migrating to version 18';
+    END IF;
+END $$;
+
+ALTER TABLE tasks ALTER COLUMN owner_id SET NOT NULL;
+ALTER TABLE projects ALTER COLUMN owner_id SET NOT NULL;
+ALTER TABLE phases ALTER COLUMN owner_id SET NOT NULL;
+ALTER TABLE tech_stack_items ALTER COLUMN owner_id SET NOT NULL;
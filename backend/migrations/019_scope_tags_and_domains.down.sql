@@ -0,0 +1,49 @@
+-- Collapse the copies of every user into one shared tag or domain each.
+-- The copy of the oldest owner survives and takes over the links of the others.
+
+-- Tags
+CREATE TEMPORARY TABLE tag_survivors ON COMMIT DROP AS
+SELECT g.tag_id AS old_id, FIRST_VALUE(g.tag_id) OVER (PARTITION BY LOWER(g.name) ORDER BY u.created_at, g.tag_id) AS new_id
+FROM tags g JOIN users u ON u.user_id = g.owner_id;
+
+INSERT INTO task_tags (task_id, tag_id)
+SELECT tt.task_id, s.new_id FROM task_tags tt JOIN tag_survivors s ON s.old_id = tt.tag_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO project_tags (project_id, tag_id)
+SELECT pt.project_id, s.new_id FROM project_tags pt JOIN tag_survivors s ON s.old_id = pt.tag_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM tags g USING tag_survivors s WHERE s.old_id = g.tag_id AND s.old_id <> s.new_id;
+
+DROP INDEX IF EXISTS idx_tags_owner_name;
+ALTER TABLE tags DROP COLUMN IF EXISTS owner_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (LOWER(name));
+
+-- Domains, tasks keep their slug
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_tasks_domain;
+ALTER TABLE domains DROP CONSTRAINT IF EXISTS uq_domains_owner_slug;
+ALTER TABLE domains DROP CONSTRAINT IF EXISTS uq_domains_owner_position;
+
+DELETE FROM domains d USING domains o, users du, users ou
+WHERE o.slug = d.slug AND du.user_id = d.owner_id AND ou.user_id = o.owner_id
+    AND (ou.created_at, o.domain_id) < (du.created_at, d.domain_id);
+
+UPDATE domains d SET position = ordered.new_position
+FROM (SELECT domain_id, ROW_NUMBER() OVER (ORDER BY position, slug) - 1 AS new_position FROM domains) ordered
+WHERE d.domain_id = ordered.domain_id;
+
+ALTER TABLE domains DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE domains ADD CONSTRAINT domains_slug_key UNIQUE (slug);
+ALTER TABLE domains ADD CONSTRAINT uq_domains_position UNIQUE (position) DEFERRABLE INITIALLY DEFERRED;
+
+-- Without users the defaults are the shared domains again
+INSERT INTO domains (slug, display_name, color, icon, position)
+SELECT slug, display_name, color, icon, position FROM default_domains
+WHERE NOT EXISTS (SELECT 1 FROM domains);
+
+ALTER TABLE tasks
+ADD CONSTRAINT fk_tasks_domain
+FOREIGN KEY (domain) REFERENCES domains(slug) ON UPDATE CASCADE;
+
+DROP TABLE IF EXISTS default_domains;
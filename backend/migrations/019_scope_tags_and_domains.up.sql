@@ -0,0 +1,78 @@
+-- Every user keeps their own tags and domains, like the tech stack.
+
+-- Tags: the oldest user gets a copy of every tag, everyone else a copy of
+-- the tags on their own tasks and projects. Links move to the owner's copy.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+DROP INDEX IF EXISTS idx_tags_name;
+
+CREATE TEMPORARY TABLE tag_copies ON COMMIT DROP AS
+SELECT g.tag_id AS old_id, u.user_id AS owner_id, gen_random_uuid() AS new_id
+FROM tags g CROSS JOIN users u
+WHERE g.owner_id IS NULL AND (
+    u.user_id = (SELECT user_id FROM users ORDER BY created_at LIMIT 1)
+    OR EXISTS (SELECT 1 FROM task_tags tt JOIN tasks t ON t.task_id = tt.task_id WHERE tt.tag_id = g.tag_id AND t.owner_id = u.user_id)
+    OR EXISTS (SELECT 1 FROM project_tags pt JOIN projects p ON p.project_id = pt.project_id WHERE pt.tag_id = g.tag_id AND p.owner_id = u.user_id)
+);
+
+INSERT INTO tags (tag_id, name, color, owner_id, created_at, updated_at)
+SELECT c.new_id, g.name, g.color, c.owner_id, g.created_at, g.updated_at
+FROM tag_copies c JOIN tags g ON g.tag_id = c.old_id;
+
+UPDATE task_tags tt SET tag_id = c.new_id
+FROM tag_copies c, tasks t
+WHERE tt.tag_id = c.old_id AND t.task_id = tt.task_id AND t.owner_id = c.owner_id;
+
+UPDATE project_tags pt SET tag_id = c.new_id
+FROM tag_copies c, projects p
+WHERE pt.tag_id = c.old_id AND p.project_id = pt.project_id AND p.owner_id = c.owner_id;
+
+DELETE FROM tags WHERE owner_id IS NULL;
+ALTER TABLE tags ALTER COLUMN owner_id SET NOT NULL;
+
+-- Tag names are unique per user regardless of case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_owner_name ON tags (owner_id, LOWER(name));
+
+-- Domains: new users start with the domains migration 012 seeded
+CREATE TABLE IF NOT EXISTS default_domains (
+    slug TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    icon TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL UNIQUE
+);
+
+INSERT INTO default_domains (slug, display_name, color, icon, position) VALUES
+    ('work', 'Work', '#3B82F6', 'briefcase', 0),
+    ('university', 'University', '#A855F7', 'graduation-cap', 1),
+    ('personal', 'Personal', '#06B6D4', 'user', 2),
+    ('coding', 'Coding', '#64748B', 'code', 3),
+    ('health', 'Health', '#10B981', 'heart', 4),
+    ('finance', 'Finance', '#F59E0B', 'wallet', 5),
+    ('social', 'Social', '#F97316', 'users', 6),
+    ('home', 'Home', '#EF4444', 'home', 7),
+    ('study', 'Study', '#6366F1', 'book-open', 8),
+    ('travel', 'Travel', '#0EA5E9', 'plane', 9),
+    ('administration', 'Administration', '#6B7280', 'settings', 10)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Existing users get a copy of every domain, so all their tasks keep theirs
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_tasks_domain;
+ALTER TABLE domains DROP CONSTRAINT IF EXISTS domains_slug_key;
+ALTER TABLE domains DROP CONSTRAINT IF EXISTS uq_domains_position;
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(user_id) ON DELETE CASCADE;
+
+INSERT INTO domains (slug, display_name, color, icon, position, archived, owner_id, created_at, updated_at)
+SELECT d.slug, d.display_name, d.color, d.icon, d.position, d.archived, u.user_id, d.created_at, d.updated_at
+FROM domains d CROSS JOIN users u
+WHERE d.owner_id IS NULL;
+
+DELETE FROM domains WHERE owner_id IS NULL;
+ALTER TABLE domains ALTER COLUMN owner_id SET NOT NULL;
+
+ALTER TABLE domains ADD CONSTRAINT uq_domains_owner_slug UNIQUE (owner_id, slug);
+ALTER TABLE domains ADD CONSTRAINT uq_domains_owner_position UNIQUE (owner_id, position) DEFERRABLE INITIALLY DEFERRED;
+
+-- A task can only be filed under a domain of its owner
+ALTER TABLE tasks
+ADD CONSTRAINT fk_tasks_domain
+FOREIGN KEY (owner_id, domain) REFERENCES domains(owner_id, slug) ON UPDATE CASCADE;
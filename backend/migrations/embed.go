@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the server binary can apply them itself.
+package migrations
+
+import "embed"
+
+// FS holds every NNN_name.up.sql and NNN_name.down.sql file of this directory
+//
+//go:embed *.sql
+var FS embed.FS
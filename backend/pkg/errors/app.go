@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+// Kind decides the status code an Error is answered with
+type Kind string
+
+const (
+	KindValidation           Kind = "validation"
+	KindNotFound             Kind = "not_found"
+	KindConflict             Kind = "conflict"
+	KindUnauthorized         Kind = "unauthorized"
+	KindForbidden            Kind = "forbidden"
+	KindPreconditionFailed   Kind = "precondition_failed"
+	KindPreconditionRequired Kind = "precondition_required"
+	KindInternal             Kind = "internal"
+)
+
+// Error is an application error with a stable machine-readable code. The
+// sentinels of this package are *Error values, so errors.Is keeps matching
+// them and errors.As finds them through any number of fmt.Errorf("%w") wraps.
+type Error struct {
+	Kind Kind
+	// Code is stable across releases, clients switch on it instead of Detail
+	Code string
+	// Field is the JSON field a validation error is about, empty if none
+	Field  string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func Validation(code string, field string, detail string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Field: field, Detail: detail}
+}
+
+func NotFound(code string, detail string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Detail: detail}
+}
+
+func Conflict(code string, detail string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Detail: detail}
+}
+
+func Unauthorized(code string, detail string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Detail: detail}
+}
+
+func Forbidden(code string, detail string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Detail: detail}
+}
+
+func PreconditionFailed(code string, detail string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Detail: detail}
+}
+
+func PreconditionRequired(code string, detail string) *Error {
+	return &Error{Kind: KindPreconditionRequired, Code: code, Detail: detail}
+}
+
+func Internal(code string, detail string) *Error {
+	return &Error{Kind: KindInternal, Code: code, Detail: detail}
+}
+
+// From returns the first *Error in the chain of err
+func From(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// Violations is a validation error listing every invalid field of a request.
+// errors.Is and errors.As look into each violation.
+type Violations []*Error
+
+func (v Violations) Error() string {
+	details := make([]string, len(v))
+	for i, violation := range v {
+		details[i] = violation.Detail
+	}
+	return strings.Join(details, "; ")
+}
+
+func (v Violations) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, violation := range v {
+		errs[i] = violation
+	}
+	return errs
+}
@@ -0,0 +1,20 @@
+package errors
+
+var (
+	// Authentication Errors
+	ErrInvalidCredentials  = Unauthorized("invalid_credentials", "invalid email or password")
+	ErrInvalidAccessToken  = Unauthorized("invalid_access_token", "invalid or expired access token")
+	ErrInvalidRefreshToken = Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+	ErrRefreshTokenReused  = Unauthorized("refresh_token_reused", "refresh token was already used, please log in again")
+	ErrMissingCredentials  = Validation("missing_credentials", "", "email and password are required")
+	ErrMissingOwner        = Internal("missing_owner", "no authenticated user in request context")
+	ErrPasswordTooShort    = Validation("password_too_short", "password", "password must be at least 12 characters")
+
+	// API Token Errors
+	ErrMissingScopes     = Validation("missing_scopes", "scopes", "at least one scope is required")
+	ErrInvalidScope      = Validation("invalid_scope", "scopes", "scopes must be read, tasks:write or projects:write")
+	ErrExpiryInPast      = Validation("expiry_in_past", "expires_at", "expires_at must be in the future")
+	ErrUnknownAPIToken   = NotFound("api_token_not_found", "unknown api token")
+	ErrInsufficientScope = Forbidden("insufficient_scope", "api token lacks the scope for this request")
+	ErrSessionRequired   = Forbidden("session_required", "api tokens can't be used here, log in instead")
+)
@@ -0,0 +1,28 @@
+package errors
+
+var (
+	// Tech Stack Conflict Errors
+	ErrTechStackNameTaken = Conflict("tech_stack_name_taken", "a tech stack item with this name already exists")
+
+	// User Conflict Errors
+	ErrEmailTaken = Conflict("email_taken", "a user with this email already exists")
+
+	// Tag Conflict Errors
+	ErrTagNameTaken = Conflict("tag_name_taken", "a tag with this name already exists, merge into it instead")
+
+	// Domain Conflict Errors
+	ErrDomainSlugTaken = Conflict("domain_slug_taken", "a domain with this slug already exists")
+	ErrDomainInUse     = Conflict("domain_in_use", "domain still has tasks, merge or archive it instead")
+
+	// Task Conflict Errors
+	ErrOpenChecklistItems = Conflict("open_checklist_items", "task still has open checklist items")
+	ErrDependencyExists   = Conflict("dependency_exists", "task is already blocked by this task")
+
+	// Phase Conflict Errors
+	ErrPhaseHasOpenTasks = Conflict("phase_has_open_tasks", "phase still has open tasks and can't be finished")
+
+	// Concurrent Modification Errors
+	ErrTaskModified    = PreconditionFailed("task_modified", "task was changed since it was read")
+	ErrProjectModified = PreconditionFailed("project_modified", "project was changed since it was read")
+	ErrIfMatchRequired = PreconditionRequired("if_match_required", "send the ETag of the record in If-Match to change it")
+)
@@ -0,0 +1,22 @@
+package errors
+
+var (
+	// Task Not Found Errors
+	ErrTaskNotFound          = NotFound("task_not_found", "task not found")
+	ErrChecklistItemNotFound = NotFound("checklist_item_not_found", "checklist item not found")
+	ErrDependencyNotFound    = NotFound("dependency_not_found", "dependency not found")
+
+	// Project Not Found Errors
+	ErrProjectNotFound       = NotFound("project_not_found", "project not found")
+	ErrPhaseNotFound         = NotFound("phase_not_found", "phase not found")
+	ErrTechStackItemNotFound = NotFound("tech_stack_item_not_found", "tech stack item not found")
+
+	// Tag Not Found Errors
+	ErrTagNotFound = NotFound("tag_not_found", "tag not found")
+
+	// Domain Not Found Errors
+	ErrDomainNotFound = NotFound("domain_not_found", "domain not found")
+
+	// User Not Found Errors
+	ErrUserNotFound = NotFound("user_not_found", "user not found")
+)
@@ -1,20 +1,67 @@
 package errors
 
-import "errors"
-
 var (
 	// Generic Validation Errors
-	ErrTitleRequired      = errors.New("title is required")
-	ErrInvalidPriority    = errors.New("invalid priority value")
-	ErrInvalidDomain      = errors.New("invalid domain value")
-	ErrMissingId          = errors.New("id is required")
-	ErrMissingDescription = errors.New("description is required")
-	ErrInvalidStatus      = errors.New("invalid status value")
+	ErrTitleRequired      = Validation("title_required", "title", "title is required")
+	ErrInvalidPriority    = Validation("invalid_priority", "priority", "invalid priority value")
+	ErrInvalidDomain      = Validation("invalid_domain", "domain", "invalid domain value")
+	ErrMissingId          = Validation("id_required", "", "id is required")
+	ErrMissingDescription = Validation("description_required", "description", "description is required")
+	ErrInvalidStatus      = Validation("invalid_status", "status", "invalid status value")
 
 	// Task Specific Validation Errors
-	ErrNoDeadlineForNonBacklog = errors.New("deadline must be set for non-backlog tasks")
-	ErrBacklogDeadlineConflict = errors.New("backlog tasks should not have a deadline")
+	ErrNoDeadlineForNonBacklog = Validation("no_deadline_for_non_backlog", "deadline", "deadline must be set for non-backlog tasks")
+	ErrBacklogDeadlineConflict = Validation("backlog_deadline_conflict", "deadline", "backlog tasks should not have a deadline")
+	ErrUnknownProject          = Validation("unknown_project", "project_id", "unknown project")
+	ErrPhaseWithoutProject     = Validation("phase_without_project", "phase_id", "phase_id requires a project_id")
+	ErrPhaseNotInProject       = Validation("phase_not_in_project", "phase_id", "phase does not belong to the given project")
+
+	// Task Recurrence Validation Errors
+	ErrInvalidFrequency        = Validation("invalid_frequency", "recurrence.frequency", "invalid recurrence frequency")
+	ErrInvalidInterval         = Validation("invalid_interval", "recurrence.interval", "recurrence interval must be positive")
+	ErrInvalidWeekday          = Validation("invalid_weekday", "recurrence.by_weekday", "by_weekday needs weekly frequency and values from 0 (sunday) to 6 (saturday)")
+	ErrRecurrenceEndConflict   = Validation("recurrence_end_conflict", "recurrence.count", "recurrence can end either by until or by count, not both")
+	ErrInvalidOccurrenceCount  = Validation("invalid_occurrence_count", "recurrence.count", "recurrence count must be positive")
+	ErrRecurrenceNeedsDeadline = Validation("recurrence_needs_deadline", "deadline", "recurring tasks need a deadline")
+	ErrTaskNotRecurring        = Validation("task_not_recurring", "", "task is not recurring")
+	ErrRecurrenceEnded         = Validation("recurrence_ended", "", "recurrence has no further occurrences")
+	ErrInvalidPreviewCount     = Validation("invalid_preview_count", "count", "preview count must be between 1 and 50")
+
+	// Task Checklist Validation Errors
+	ErrInvalidChecklistReorder = Validation("invalid_checklist_reorder", "item_ids", "reorder must list every checklist item of the task exactly once")
+
+	// Task Dependency Validation Errors
+	ErrSelfDependency     = Validation("self_dependency", "blocked_by_task_id", "a task can't block itself")
+	ErrUnknownBlocker     = Validation("unknown_blocker", "blocked_by_task_id", "unknown blocking task")
+	ErrDependencyCycle    = Validation("dependency_cycle", "blocked_by_task_id", "dependency would create a cycle")
+	ErrDependencyDeadline = Validation("dependency_deadline", "deadline", "a task can't be due before the tasks blocking it")
+
+	// Tag Validation Errors
+	ErrUnknownTag       = Validation("unknown_tag", "tag_ids", "unknown tag")
+	ErrTagMergeIntoSelf = Validation("tag_merge_into_self", "into_tag_id", "a tag can't be merged into itself")
+
+	// Task Filter Validation Errors
+	ErrInvalidSortField     = Validation("invalid_sort_field", "sort", "invalid sort field")
+	ErrInvalidLimit         = Validation("invalid_limit", "limit", "limit must be between 1 and 200")
+	ErrInvalidCursor        = Validation("invalid_cursor", "cursor", "invalid cursor")
+	ErrInvalidDeadlineRange = Validation("invalid_deadline_range", "deadline_from", "deadline_from must not be after deadline_to")
 
 	// Project Specific Validation Errors
-	ErrNoTechStackItems = errors.New("at least one tech stack item is required")
+	ErrNoTechStackItems     = Validation("tech_stack_required", "tech_stack_ids", "at least one tech stack item is required")
+	ErrUnknownTechStackItem = Validation("unknown_tech_stack_item", "tech_stack_ids", "unknown tech stack item")
+
+	// Tech Stack Specific Validation Errors
+	ErrNameRequired   = Validation("name_required", "name", "name is required")
+	ErrInvalidColor   = Validation("invalid_color", "color", "color must be a hex value like #1a2b3c")
+	ErrInvalidReorder = Validation("invalid_reorder", "tech_stack_item_ids", "reorder must list every tech stack item exactly once")
+
+	// Phase Specific Validation Errors
+	ErrInvalidPhaseReorder = Validation("invalid_phase_reorder", "phase_ids", "reorder must list every phase of the project exactly once")
+
+	// Domain Specific Validation Errors
+	ErrInvalidSlug          = Validation("invalid_slug", "slug", "slug must be lowercase letters and digits separated by single dashes")
+	ErrDisplayNameRequired  = Validation("display_name_required", "display_name", "display name is required")
+	ErrInvalidDomainReorder = Validation("invalid_domain_reorder", "domain_ids", "reorder must list every domain exactly once")
+	ErrDomainMergeIntoSelf  = Validation("domain_merge_into_self", "into_domain_id", "a domain can't be merged into itself")
+	ErrArchivedDomain       = Validation("archived_domain", "domain", "domain is archived and can't take new tasks")
 )
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag is the entity tag of a record at the given version
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag sets the ETag header of a response carrying a record at the given version
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// HasIfMatch reports whether r carries an If-Match header. Writes to
+// versioned records are answered 428 Precondition Required without one.
+func HasIfMatch(r *http.Request) bool {
+	return len(r.Header.Values("If-Match")) > 0
+}
+
+// IfMatch reports whether the If-Match header of r allows writing a record
+// whose current entity tag is etag. Requests without the header never do,
+// weak tags never match because If-Match compares strongly.
+func IfMatch(r *http.Request, etag string) bool {
+	for _, value := range r.Header.Values("If-Match") {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RespondWithPreconditionFailed sends a 412 Precondition Failed response with
+// the current version of the record, so the client can merge and retry.
+func RespondWithPreconditionFailed(w http.ResponseWriter, version int, payload any) {
+	SetETag(w, version)
+	RespondWithJSON(w, http.StatusPreconditionFailed, payload)
+}
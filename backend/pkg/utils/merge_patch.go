@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies the JSON Merge Patch (RFC 7396) patch to the JSON
+// document target. Members set to null are removed, objects are merged
+// recursively and every other value, arrays included, replaces the old one.
+func MergePatch(target []byte, patch []byte) ([]byte, error) {
+	var targetValue, patchValue any
+	if err := json.Unmarshal(target, &targetValue); err != nil {
+		return nil, fmt.Errorf("invalid target document: %w", err)
+	}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return json.Marshal(mergePatch(targetValue, patchValue))
+}
+
+// DecodeMergePatch applies patch to the JSON encoding of document and decodes
+// the result into v. Members v has no field for are rejected.
+func DecodeMergePatch(document any, patch []byte, v any) error {
+	target, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode target document: %w", err)
+	}
+
+	merged, err := MergePatch(target, patch)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// ValueOf returns the value p points to, or the zero value when p is nil
+func ValueOf[T any](p *T) T {
+	var value T
+	if p != nil {
+		value = *p
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// The examples of RFC 7396, appendix A
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{"replaces a member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"adds a member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null deletes a member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"null keeps the other members", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces a string", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"string replaces an array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested objects are merged", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are replaced whole", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"array patch replaces the document", `["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{"array patch replaces an object", `{"a":"b"}`, `["c"]`, `["c"]`},
+		{"null patch replaces the document", `{"a":"foo"}`, `null`, `null`},
+		{"string patch replaces the document", `{"a":"foo"}`, `"bar"`, `"bar"`},
+		{"nulls in the target are kept", `{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{"object patch on an array", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{"new nested members drop nulls", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergePatch([]byte(tt.target), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch failed: %v", err)
+			}
+
+			var got, want any
+			if err := json.Unmarshal(merged, &got); err != nil {
+				t.Fatalf("MergePatch returned invalid JSON %s: %v", merged, err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid expectation %s: %v", tt.want, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("MergePatch = %s, want %s", merged, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePatchInvalidJSON(t *testing.T) {
+	if _, err := MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`)); err == nil {
+		t.Fatal("MergePatch accepted an invalid patch")
+	}
+}
+
+// patchDocument stands in for the update requests the handlers patch
+type patchDocument struct {
+	Title    string            `json:"title"`
+	Deadline *string           `json:"deadline"`
+	TagIds   []string          `json:"tag_ids"`
+	Links    map[string]string `json:"links"`
+}
+
+func TestDecodeMergePatch(t *testing.T) {
+	deadline := "2026-05-01"
+	document := patchDocument{
+		Title:    "Buy milk",
+		Deadline: &deadline,
+		TagIds:   []string{"a", "b"},
+		Links:    map[string]string{"shop": "https://example.com", "list": "https://example.org"},
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    patchDocument
+		wantErr bool
+	}{
+		{
+			name:  "empty patch keeps everything",
+			patch: `{}`,
+			want:  document,
+		},
+		{
+			name:  "null deletes a member",
+			patch: `{"deadline": null}`,
+			want:  patchDocument{Title: "Buy milk", TagIds: []string{"a", "b"}, Links: document.Links},
+		},
+		{
+			name:  "nested objects are merged",
+			patch: `{"links": {"list": null, "recipe": "https://example.net"}}`,
+			want:  patchDocument{Title: "Buy milk", Deadline: &deadline, TagIds: []string{"a", "b"}, Links: map[string]string{"shop": "https://example.com", "recipe": "https://example.net"}},
+		},
+		{
+			name:  "arrays are replaced whole",
+			patch: `{"tag_ids": ["c"]}`,
+			want:  patchDocument{Title: "Buy milk", Deadline: &deadline, TagIds: []string{"c"}, Links: document.Links},
+		},
+		{
+			name:    "unknown members are rejected",
+			patch:   `{"owner_id": "someone else"}`,
+			wantErr: true,
+		},
+		{
+			name:    "array patch replaces the object",
+			patch:   `["title"]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON is rejected",
+			patch:   `{"title": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got patchDocument
+			err := DecodeMergePatch(document, []byte(tt.patch), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DecodeMergePatch = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeMergePatch failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DecodeMergePatch = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,134 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is stable and meant for
+// clients, Detail is meant for people.
+type Problem struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+	Code   string         `json:"code"`
+	Errors []FieldProblem `json:"errors,omitempty"`
+}
+
+// FieldProblem names the JSON field a validation error is about, if any
+type FieldProblem struct {
+	Field  string `json:"field,omitempty"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// kindStatus is the status code every error kind is answered with
+var kindStatus = map[errorutils.Kind]int{
+	errorutils.KindValidation:           http.StatusBadRequest,
+	errorutils.KindNotFound:             http.StatusNotFound,
+	errorutils.KindConflict:             http.StatusConflict,
+	errorutils.KindUnauthorized:         http.StatusUnauthorized,
+	errorutils.KindForbidden:            http.StatusForbidden,
+	errorutils.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	errorutils.KindPreconditionRequired: http.StatusPreconditionRequired,
+}
+
+// statusCodes are the codes of errors that don't carry their own
+var statusCodes = map[int]string{
+	http.StatusBadRequest:           "bad_request",
+	http.StatusUnauthorized:         "unauthorized",
+	http.StatusForbidden:            "forbidden",
+	http.StatusNotFound:             "not_found",
+	http.StatusConflict:             "conflict",
+	http.StatusPreconditionFailed:   "precondition_failed",
+	http.StatusPreconditionRequired: "precondition_required",
+	http.StatusInternalServerError:  "internal",
 }
 
-// RespondWithError sends a JSON error response with the given status code and message.
+// RespondWithError sends a problem+json response with the given status code and message.
 func RespondWithError(w http.ResponseWriter, code int, message string) {
-	RespondWithJSON(w, code, ErrorResponse{Error: message})
+	respondWithProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: message,
+		Code:   statusCodes[code],
+	})
+}
+
+// RespondWithProblem answers a typed application error in err with its status
+// and code. Validation errors, one or several, are always listed under
+// validation_failed. Any other error is logged and answered with a 500 carrying message.
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, err error, message string) {
+	var violations errorutils.Violations
+	if errors.As(err, &violations) && len(violations) > 0 {
+		respondWithViolations(w, violations)
+		return
+	}
+
+	appErr, ok := errorutils.From(err)
+	if !ok {
+		RespondWithServerError(w, r, err, message)
+		return
+	}
+	if appErr.Kind == errorutils.KindValidation {
+		respondWithViolations(w, errorutils.Violations{appErr})
+		return
+	}
+	status, known := kindStatus[appErr.Kind]
+	if !known {
+		RespondWithServerError(w, r, err, message)
+		return
+	}
+
+	respondWithProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: appErr.Detail,
+		Code:   appErr.Code,
+	})
+}
+
+// respondWithViolations lists every invalid field of the request
+func respondWithViolations(w http.ResponseWriter, violations errorutils.Violations) {
+	detail := violations[0].Detail
+	if len(violations) > 1 {
+		detail = fmt.Sprintf("%d fields are invalid", len(violations))
+	}
+
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: detail,
+		Code:   "validation_failed",
+		Errors: make([]FieldProblem, len(violations)),
+	}
+	for i, violation := range violations {
+		problem.Errors[i] = FieldProblem{Field: violation.Field, Code: violation.Code, Detail: violation.Detail}
+	}
+	respondWithProblem(w, problem)
+}
+
+func respondWithProblem(w http.ResponseWriter, problem Problem) {
+	response, err := json.Marshal(problem)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(response)
 }
 
 // RespondWithNoContent sends a 204 No Content response.
@@ -43,16 +161,33 @@ func RespondWithNotFound(w http.ResponseWriter, message string) {
 	RespondWithError(w, http.StatusNotFound, message)
 }
 
+// RespondWithConflict sends a 409 Conflict response with the given error message.
+func RespondWithConflict(w http.ResponseWriter, message string) {
+	RespondWithError(w, http.StatusConflict, message)
+}
+
 // RespondWithInternalError sends a 500 Internal Server Error response with the given error message.
 func RespondWithInternalError(w http.ResponseWriter, message string) {
 	RespondWithError(w, http.StatusInternalServerError, message)
 }
 
+// RespondWithServerError logs err with the request context and sends a 500
+// Internal Server Error response with the given message, err never reaches the client.
+func RespondWithServerError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	slog.ErrorContext(r.Context(), message, "error", err)
+	RespondWithInternalError(w, message)
+}
+
 // RespondWithUnauthorized sends a 401 Unauthorized response with the given error message.
 func RespondWithUnauthorized(w http.ResponseWriter, message string) {
 	RespondWithError(w, http.StatusUnauthorized, message)
 }
 
+// RespondWithForbidden sends a 403 Forbidden response with the given error message.
+func RespondWithForbidden(w http.ResponseWriter, message string) {
+	RespondWithError(w, http.StatusForbidden, message)
+}
+
 // RespondWithRecordNotFound sends a 404 Not Found response for a missing record.
 func RespondWithRecordNotFound(w http.ResponseWriter, record string) {
 	RespondWithError(w, http.StatusNotFound, record+" not found")
@@ -0,0 +1,77 @@
+// Package validation collects every field violation of a request, so clients
+// learn about all invalid fields in one response.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	errorutils "github.com/J0kerul/jokers-hub/pkg/errors"
+)
+
+type Validator struct {
+	violations errorutils.Violations
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Check records err unless ok
+func (v *Validator) Check(ok bool, err *errorutils.Error) {
+	if !ok {
+		v.violations = append(v.violations, err)
+	}
+}
+
+// Required records err if value is blank
+func (v *Validator) Required(value string, err *errorutils.Error) {
+	v.Check(strings.TrimSpace(value) != "", err)
+}
+
+// MaxLength records a too_long violation of field if value has more than max characters
+func (v *Validator) MaxLength(field string, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max,
+		errorutils.Validation("too_long", field, fmt.Sprintf("%s must be at most %d characters", field, max)))
+}
+
+// URL records an invalid_url violation of field unless value is empty or an
+// absolute http or https URL
+func (v *Validator) URL(field string, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	valid := err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+	v.Check(valid, errorutils.Validation("invalid_url", field, field+" must be an http or https URL"))
+}
+
+// Collect records the validation errors in err and returns any other error,
+// so checks that query the database can still fail
+func (v *Validator) Collect(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var violations errorutils.Violations
+	if errors.As(err, &violations) {
+		v.violations = append(v.violations, violations...)
+		return nil
+	}
+	if appErr, ok := errorutils.From(err); ok && appErr.Kind == errorutils.KindValidation {
+		v.violations = append(v.violations, appErr)
+		return nil
+	}
+	return err
+}
+
+// Err returns the recorded violations, or nil if there are none
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return v.violations
+}
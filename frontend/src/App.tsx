@@ -1,15 +1,38 @@
-import { useEffect } from "react";
+import { useEffect, useState } from "react";
 import { BrowserRouter, Routes, Route } from "react-router-dom";
 import TaskManager from "./pages/TaskManager";
 import Dashboard from "./pages/Dashboard";
 import ProjectManager from "./pages/ProjectManager";
+import Login from "./pages/Login";
+import { apiClient } from "./services/apiClient";
+import { authService, type User } from "./services/authService";
 
 function App() {
+  // undefined while the session from the refresh cookie is restored
+  const [user, setUser] = useState<User | null | undefined>(undefined);
+
   useEffect(() => {
     // Dark Mode by default
     document.documentElement.classList.add("dark");
   }, []);
 
+  useEffect(() => {
+    apiClient.onUnauthenticated(() => setUser(null));
+    authService
+      .restoreSession()
+      .then(setUser)
+      .catch(() => setUser(null));
+    return () => apiClient.onUnauthenticated(null);
+  }, []);
+
+  if (user === undefined) {
+    return <div className="min-h-screen" />;
+  }
+
+  if (user === null) {
+    return <Login onLogin={setUser} />;
+  }
+
   return (
     <div className="min-h-screen">
       <BrowserRouter>
@@ -5,10 +5,12 @@ import {
   Home,
   CheckSquare,
   Code2,
+  LogOut,
 } from "lucide-react";
 import { JokerIcon } from "./icons/JokerIcon";
 import { useState } from "react";
 import { Link, useLocation } from "react-router-dom";
+import { authService } from "@/services/authService";
 
 type SidebarProps = {
   isOpen: boolean;
@@ -27,6 +29,15 @@ export function Sidebar({ isOpen, onClose }: SidebarProps) {
     }
   };
 
+  const handleLogout = async () => {
+    try {
+      await authService.logout();
+    } finally {
+      // Reload so no state of the previous session survives
+      window.location.assign("/");
+    }
+  };
+
   const categories = [
     {
       name: "Home",
@@ -122,6 +133,17 @@ export function Sidebar({ isOpen, onClose }: SidebarProps) {
             );
           })}
         </div>
+
+        {/* Footer */}
+        <div className="absolute bottom-0 left-0 right-0 p-4 border-t border-border">
+          <button
+            onClick={handleLogout}
+            className="w-full flex items-center gap-2 px-3 py-2 text-sm hover:bg-muted rounded-md transition-colors cursor-pointer"
+          >
+            <LogOut className="w-4 h-4" />
+            Log out
+          </button>
+        </div>
       </div>
     </>
   );
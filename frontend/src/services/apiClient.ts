@@ -0,0 +1,124 @@
+const API_BASE_URL = import.meta.env.VITE_API_URL || "http://localhost:8080/api";
+
+// Problem Details body the backend answers errors with
+type Problem = {
+  title?: string;
+  status?: number;
+  detail?: string;
+  code?: string;
+  errors?: { field?: string; code: string; detail: string }[];
+};
+
+export class ApiError extends Error {
+  status: number;
+  code?: string;
+  errors: NonNullable<Problem["errors"]>;
+
+  constructor(status: number, problem: Problem) {
+    super(problem.errors?.[0]?.detail || problem.detail || problem.title || `API Error: ${status}`);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = problem.code;
+    this.errors = problem.errors ?? [];
+  }
+}
+
+export type ApiResponse<T> = {
+  data: T;
+  headers: Headers;
+};
+
+export type Session = {
+  access_token: string;
+  token_type: string;
+  expires_in: number;
+  user: {
+    user_id: string;
+    email: string;
+    created_at: string;
+  };
+};
+
+// The access token only lives in memory, the refresh token is an HttpOnly
+// cookie scoped to /api/auth that the browser sends on refresh
+let accessToken: string | null = null;
+let refreshing: Promise<Session | null> | null = null;
+let onUnauthenticated: (() => void) | null = null;
+
+class ApiClient {
+  setSession(session: Session | null) {
+    accessToken = session?.access_token ?? null;
+  }
+
+  // Called when a request stays unauthorized after a refresh attempt
+  onUnauthenticated(callback: (() => void) | null) {
+    onUnauthenticated = callback;
+  }
+
+  // Rotates the refresh cookie, concurrent callers share one request
+  refresh(): Promise<Session | null> {
+    if (!refreshing) {
+      refreshing = this.send(`${API_BASE_URL}/auth/refresh`, { method: "POST" })
+        .then(async (response) => {
+          if (!response.ok) {
+            this.setSession(null);
+            return null;
+          }
+          const session: Session = await response.json();
+          this.setSession(session);
+          return session;
+        })
+        .finally(() => {
+          refreshing = null;
+        });
+    }
+    return refreshing;
+  }
+
+  async request<T>(endpoint: string, options?: RequestInit): Promise<T> {
+    const { data } = await this.requestWithHeaders<T>(endpoint, options);
+    return data;
+  }
+
+  async requestWithHeaders<T>(endpoint: string, options?: RequestInit): Promise<ApiResponse<T>> {
+    const url = `${API_BASE_URL}${endpoint}`;
+    let response = await this.send(url, options);
+
+    // Access tokens are short lived, refresh once and retry
+    if (response.status === 401 && !endpoint.startsWith("/auth/")) {
+      const session = await this.refresh();
+      if (session) {
+        response = await this.send(url, options);
+      }
+      if (response.status === 401) {
+        onUnauthenticated?.();
+      }
+    }
+
+    if (!response.ok) {
+      const problem: Problem = await response.json().catch(() => ({}));
+      throw new ApiError(response.status, problem);
+    }
+
+    // Leere Responses (wie DELETE 204) nicht parsen!
+    if (response.status === 204 || response.headers.get("content-length") === "0") {
+      return { data: undefined as T, headers: response.headers };
+    }
+
+    return { data: await response.json(), headers: response.headers };
+  }
+
+  private send(url: string, options?: RequestInit): Promise<Response> {
+    return fetch(url, {
+      ...options,
+      credentials: "include",
+      headers: {
+        "Content-Type": "application/json",
+        ...(accessToken ? { Authorization: `Bearer ${accessToken}` } : {}),
+        ...options?.headers,
+      },
+    });
+  }
+}
+
+export const apiClient = new ApiClient();
@@ -0,0 +1,30 @@
+import { apiClient, type Session } from "./apiClient";
+
+export type User = Session["user"];
+
+class AuthService {
+  async login(email: string, password: string): Promise<User> {
+    const session = await apiClient.request<Session>("/auth/login", {
+      method: "POST",
+      body: JSON.stringify({ email, password }),
+    });
+    apiClient.setSession(session);
+    return session.user;
+  }
+
+  // Picks up an existing session from the refresh cookie after a reload
+  async restoreSession(): Promise<User | null> {
+    const session = await apiClient.refresh();
+    return session?.user ?? null;
+  }
+
+  async logout(): Promise<void> {
+    try {
+      await apiClient.request<void>("/auth/logout", { method: "POST" });
+    } finally {
+      apiClient.setSession(null);
+    }
+  }
+}
+
+export const authService = new AuthService();
@@ -1,4 +1,4 @@
-const API_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080';
+import { apiClient } from './apiClient';
 
 export type ProjectStatus = 
   | 'idea' 
@@ -22,6 +22,7 @@ export type Project = {
   live_url?: string;
   created_at: string;
   updated_at: string;
+  version: number;
 };
 
 export type CreateProjectRequest = {
@@ -36,64 +37,51 @@ export type CreateProjectRequest = {
 export type UpdateProjectRequest = Partial<CreateProjectRequest>;
 
 class ProjectService {
+  // Version of every project this client has seen, sent as If-Match on writes
+  private versions = new Map<string, number>();
+
+  private remember(project: Project): Project {
+    this.versions.set(project.project_id, project.version);
+    return project;
+  }
+
+  private ifMatch(id: string): Record<string, string> {
+    const version = this.versions.get(id);
+    return version === undefined ? {} : { 'If-Match': `"${version}"` };
+  }
+
   async getAllProjects(): Promise<Project[]> {
-    const response = await fetch(`${API_URL}/projects`);
-    if (!response.ok) {
-      throw new Error('Failed to fetch projects');
-    }
-    return response.json();
+    const projects = await apiClient.request<Project[]>('/projects');
+    return projects.map((project) => this.remember(project));
   }
 
   async getProjectById(id: string): Promise<Project> {
-    const response = await fetch(`${API_URL}/projects/${id}`);
-    if (!response.ok) {
-      throw new Error('Failed to fetch project');
-    }
-    return response.json();
+    return this.remember(await apiClient.request<Project>(`/projects/${id}`));
   }
 
   async createProject(project: CreateProjectRequest): Promise<Project> {
-    const response = await fetch(`${API_URL}/projects`, {
+    const created = await apiClient.request<Project>('/projects', {
       method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-      },
       body: JSON.stringify(project),
     });
-
-    if (!response.ok) {
-      const error = await response.json();
-      throw new Error(error.message || 'Failed to create project');
-    }
-
-    return response.json();
+    return this.remember(created);
   }
 
   async updateProject(id: string, project: UpdateProjectRequest): Promise<Project> {
-    const response = await fetch(`${API_URL}/projects/${id}`, {
+    const updated = await apiClient.request<Project>(`/projects/${id}`, {
       method: 'PUT',
-      headers: {
-        'Content-Type': 'application/json',
-      },
+      headers: this.ifMatch(id),
       body: JSON.stringify(project),
     });
-
-    if (!response.ok) {
-      const error = await response.json();
-      throw new Error(error.message || 'Failed to update project');
-    }
-
-    return response.json();
+    return this.remember(updated);
   }
 
   async deleteProject(id: string): Promise<void> {
-    const response = await fetch(`${API_URL}/projects/${id}`, {
+    await apiClient.request<void>(`/projects/${id}`, {
       method: 'DELETE',
+      headers: this.ifMatch(id),
     });
-
-    if (!response.ok) {
-      throw new Error('Failed to delete project');
-    }
+    this.versions.delete(id);
   }
 }
 
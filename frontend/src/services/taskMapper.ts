@@ -14,6 +14,7 @@ export type ApiTask = {
   completed: boolean;
   created_at: string;
   updated_at: string;
+  version: number;
 };
 
 // ← NEUER TYPE FÜR toApi INPUT!
@@ -51,8 +52,8 @@ export const taskMapper = {
   },
 
   // Frontend → API (für POST/PUT requests)
-  toApi(task: ApiTaskInput): Partial<Omit<ApiTask, "task_id" | "created_at" | "updated_at">> {
-    const apiTask: Partial<Omit<ApiTask, "task_id" | "created_at" | "updated_at">> = {};
+  toApi(task: ApiTaskInput): Partial<Omit<ApiTask, "task_id" | "created_at" | "updated_at" | "version">> {
+    const apiTask: Partial<Omit<ApiTask, "task_id" | "created_at" | "updated_at" | "version">> = {};
     
     if (task.title !== undefined) apiTask.title = task.title;
     if (task.description !== undefined) apiTask.description = task.description;
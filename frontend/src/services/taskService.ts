@@ -1,7 +1,9 @@
 import type { Task } from "@/types";
+import { apiClient } from "./apiClient";
 import { taskMapper, type ApiTask } from "./taskMapper";
 
-const API_BASE_URL = import.meta.env.VITE_API_URL || "http://localhost:8080/api";
+// Largest page the backend hands out
+const PAGE_SIZE = 200;
 
 export type CreateTaskRequest = Omit<Task, "id" | "createdAt" | "updatedAt">;
 export type UpdateTaskRequest = Partial<Omit<Task, "id" | "createdAt" | "updatedAt" | "deadline">> & {
@@ -9,70 +11,74 @@ export type UpdateTaskRequest = Partial<Omit<Task, "id" | "createdAt" | "updated
 };
 
 class TaskService {
-  private async fetch<T>(
-    endpoint: string,
-    options?: RequestInit
-  ): Promise<T> {
-    const response = await fetch(`${API_BASE_URL}${endpoint}`, {
-      ...options,
-      headers: {
-        "Content-Type": "application/json",
-        ...options?.headers,
-      },
-    });
-
-    if (!response.ok) {
-      const error = await response.text();
-      throw new Error(error || `API Error: ${response.status}`);
-    }
+  // Version of every task this client has seen, sent as If-Match on writes
+  // so the backend rejects edits of a task that changed in the meantime
+  private versions = new Map<string, number>();
 
-    // ← FIX: Leere Responses (wie DELETE 204) nicht parsen!
-    if (response.status === 204 || response.headers.get("content-length") === "0") {
-      return undefined as T;
-    }
+  private remember(apiTask: ApiTask): Task {
+    this.versions.set(apiTask.task_id, apiTask.version);
+    return taskMapper.toFrontend(apiTask);
+  }
 
-    return response.json();
+  private ifMatch(id: string): Record<string, string> {
+    const version = this.versions.get(id);
+    return version === undefined ? {} : { "If-Match": `"${version}"` };
   }
 
+  // The list is paginated, follow X-Next-Cursor until the last page
   async getAllTasks(): Promise<Task[]> {
-    const apiTasks = await this.fetch<ApiTask[]>("/tasks");
-    return apiTasks.map(taskMapper.toFrontend);
+    const apiTasks: ApiTask[] = [];
+    let cursor: string | null = null;
+    do {
+      const params = new URLSearchParams({ limit: String(PAGE_SIZE) });
+      if (cursor) params.set("cursor", cursor);
+
+      const { data, headers } = await apiClient.requestWithHeaders<ApiTask[]>(`/tasks?${params}`);
+      apiTasks.push(...data);
+      cursor = headers.get("X-Next-Cursor");
+    } while (cursor);
+
+    return apiTasks.map((apiTask) => this.remember(apiTask));
   }
 
   async getTaskById(id: string): Promise<Task> {
-    const apiTask = await this.fetch<ApiTask>(`/tasks/${id}`);
-    return taskMapper.toFrontend(apiTask);
+    const apiTask = await apiClient.request<ApiTask>(`/tasks/${id}`);
+    return this.remember(apiTask);
   }
 
   async createTask(task: CreateTaskRequest): Promise<Task> {
     const apiTaskData = taskMapper.toApi(task);
-    const apiTask = await this.fetch<ApiTask>("/tasks", {
+    const apiTask = await apiClient.request<ApiTask>("/tasks", {
       method: "POST",
       body: JSON.stringify(apiTaskData),
     });
-    return taskMapper.toFrontend(apiTask);
+    return this.remember(apiTask);
   }
 
   async updateTask(id: string, updates: UpdateTaskRequest): Promise<Task> {
     const apiUpdates = taskMapper.toApi(updates);
-    const apiTask = await this.fetch<ApiTask>(`/tasks/${id}`, {
+    const apiTask = await apiClient.request<ApiTask>(`/tasks/${id}`, {
       method: "PUT",
+      headers: this.ifMatch(id),
       body: JSON.stringify(apiUpdates),
     });
-    return taskMapper.toFrontend(apiTask);
+    return this.remember(apiTask);
   }
 
   async deleteTask(id: string): Promise<void> {
-    await this.fetch<void>(`/tasks/${id}`, {
+    await apiClient.request<void>(`/tasks/${id}`, {
       method: "DELETE",
+      headers: this.ifMatch(id),
     });
+    this.versions.delete(id);
   }
 
   async toggleTaskCompletion(id: string): Promise<Task> {
-    const apiTask = await this.fetch<ApiTask>(`/tasks/${id}/toggle`, {
+    const apiTask = await apiClient.request<ApiTask>(`/tasks/${id}/toggle`, {
       method: "PATCH",
+      headers: this.ifMatch(id),
     });
-    return taskMapper.toFrontend(apiTask);
+    return this.remember(apiTask);
   }
 }
 